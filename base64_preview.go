@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// previews only render the beginning of large payloads, save as file to see the rest
+const previewHexLimit = 64 * 1024
+const decompressLimit = 256 * 1024 * 1024
+
+type SniffResult struct {
+	Kind string
+	MIME string
+	Ext  string
+}
+
+var listMIMEExtension = map[string]string{
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/webp":               ".webp",
+	"image/bmp":                ".bmp",
+	"image/x-icon":             ".ico",
+	"image/svg+xml":            ".svg",
+	"application/pdf":          ".pdf",
+	"application/zip":          ".zip",
+	"application/x-gzip":       ".gz",
+	"application/zlib":         ".zz",
+	"application/json":         ".json",
+	"application/wasm":         ".wasm",
+	"application/octet-stream": ".bin",
+	"text/html":                ".html",
+	"text/xml":                 ".xml",
+	"text/plain":               ".txt",
+	"font/woff":                ".woff",
+	"font/woff2":               ".woff2",
+	"font/ttf":                 ".ttf",
+	"font/otf":                 ".otf",
+	"audio/mpeg":               ".mp3",
+	"audio/wave":               ".wav",
+	"video/mp4":                ".mp4",
+}
+
+func isZlib(data []byte) bool {
+	// CMF must be deflate and the CMF/FLG pair a multiple of 31 (RFC 1950)
+	return len(data) >= 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0
+}
+
+func sniffBytes(data []byte) SniffResult {
+	if len(data) == 0 {
+		return SniffResult{Kind: "empty", MIME: "text/plain", Ext: ".txt"}
+	}
+
+	mime := strings.SplitN(http.DetectContentType(data), ";", 2)[0]
+
+	switch {
+	case mime == "application/x-gzip":
+		return SniffResult{Kind: "gzip", MIME: mime, Ext: ".gz"}
+	case strings.HasPrefix(mime, "image/"):
+		return SniffResult{Kind: "image", MIME: mime, Ext: mimeExtension(mime)}
+	case json.Valid(data):
+		return SniffResult{Kind: "json", MIME: "application/json", Ext: ".json"}
+	case strings.HasPrefix(mime, "text/") && utf8.Valid(data):
+		if mime == "text/xml" && bytes.Contains(data[:min(len(data), 512)], []byte("<svg")) {
+			return SniffResult{Kind: "image", MIME: "image/svg+xml", Ext: ".svg"}
+		}
+
+		return SniffResult{Kind: "text", MIME: mime, Ext: mimeExtension(mime)}
+	case isZlib(data):
+		// checked after text because the 2-byte header also matches some plain text
+		return SniffResult{Kind: "zlib", MIME: "application/zlib", Ext: ".zz"}
+	}
+
+	return SniffResult{Kind: "binary", MIME: mime, Ext: mimeExtension(mime)}
+}
+
+func mimeExtension(mime string) string {
+	if ext, ok := listMIMEExtension[mime]; ok {
+		return ext
+	}
+
+	return ".bin"
+}
+
+func decompressBytes(data []byte, kind string) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+
+	switch kind {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case "zlib":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("%s data can not be decompressed", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	out, err := io.ReadAll(io.LimitReader(reader, decompressLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > decompressLimit {
+		return nil, fmt.Errorf("decompressed data is larger than %d MB", decompressLimit/1024/1024)
+	}
+
+	return out, nil
+}
+
+func hexDump(data []byte) string {
+	if len(data) <= previewHexLimit {
+		return hex.Dump(data)
+	}
+
+	return hex.Dump(data[:previewHexLimit]) + fmt.Sprintf("... %d more bytes", len(data)-previewHexLimit)
+}
+
+// clipboardBytes is data as clipboard text, binary goes as plain hex since a
+// hex dump is cut short and mixes in offsets.
+func clipboardBytes(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	return hex.EncodeToString(data)
+}
+
+func imagePreview(data []byte, name string) fyne.CanvasObject {
+	img := canvas.NewImageFromReader(bytes.NewReader(data), name)
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(300, 300))
+
+	return img
+}
+
+func saveBytesAs(w fyne.Window, data []byte, fileName string, onSaved func(name string)) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if _, err := writer.Write(data); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		if onSaved != nil {
+			onSaved(writer.URI().Name())
+		}
+	}, w)
+	save.SetFileName(fileName)
+	save.Show()
+}
+
+// decodedPreview renders data according to its sniffed type, onReplace is called
+// with the decompressed bytes when the user chooses to decompress. A nil onReplace
+// hides the decompress action.
+func decodedPreview(w fyne.Window, data []byte, onReplace func([]byte)) fyne.CanvasObject {
+	sniff := sniffBytes(data)
+
+	info := widget.NewLabel(fmt.Sprintf("%s • %s • %d bytes", strings.ToUpper(sniff.Kind), sniff.MIME, len(data)))
+
+	saveBtn := widget.NewButtonWithIcon("Save as "+sniff.Ext, theme.DocumentSaveIcon(), func() {
+		saveBytesAs(w, data, "decoded"+sniff.Ext, nil)
+	})
+
+	actions := container.NewHBox(saveBtn)
+
+	var body fyne.CanvasObject
+	switch sniff.Kind {
+	case "image":
+		body = container.NewCenter(imagePreview(data, "decoded"+sniff.Ext))
+	case "json":
+		rt := jsonToRichText(string(data))
+		rt.Wrapping = fyne.TextWrapBreak
+		body = container.NewVScroll(rt)
+	case "text", "empty":
+		lbl := widget.NewLabel(string(data))
+		lbl.Wrapping = fyne.TextWrapBreak
+		body = container.NewVScroll(lbl)
+	default:
+		if (sniff.Kind == "gzip" || sniff.Kind == "zlib") && onReplace != nil {
+			actions.Add(widget.NewButtonWithIcon("Decompress "+sniff.Kind, theme.DownloadIcon(), func() {
+				out, err := decompressBytes(data, sniff.Kind)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+
+				onReplace(out)
+			}))
+		}
+
+		dump := widget.NewLabel(hexDump(data))
+		dump.TextStyle = fyne.TextStyle{Monospace: true}
+		body = container.NewScroll(dump)
+	}
+
+	return container.NewBorder(container.NewBorder(nil, nil, info, actions), nil, nil, nil, body)
+}
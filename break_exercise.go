@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ExerciseStep struct {
+	Title       string `json:"title"`
+	Instruction string `json:"instruction"`
+	Image       string `json:"image,omitempty"`
+	Seconds     int    `json:"seconds"`
+}
+
+var exerciseDuringBreak = true
+
+var defaultExercises = []ExerciseStep{
+	{
+		Title:       "20-20-20 Eye Rule",
+		Instruction: "Look at something at least 20 feet (6 meters) away for 20 seconds.",
+		Seconds:     20,
+	},
+	{
+		Title:       "Blink & Palming",
+		Instruction: "Blink slowly 10 times, then cover your closed eyes with warm palms.",
+		Seconds:     30,
+	},
+	{
+		Title:       "Neck Stretch",
+		Instruction: "Tilt your head gently to each shoulder and hold, keep your shoulders relaxed.",
+		Seconds:     30,
+	},
+	{
+		Title:       "Shoulder Rolls",
+		Instruction: "Roll your shoulders backward slowly, then forward.",
+		Seconds:     20,
+	},
+	{
+		Title:       "Stand Up & Walk",
+		Instruction: "Stand up, straighten your back and take a short walk.",
+		Seconds:     60,
+	},
+}
+
+func exerciseFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sesterdamp", "exercises.json"), nil
+}
+
+func parseExercises(data []byte) ([]ExerciseStep, error) {
+	var steps []ExerciseStep
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, err
+	}
+
+	for i, step := range steps {
+		if step.Title == "" {
+			return nil, fmt.Errorf("exercise %d: title must be fill", i+1)
+		}
+		if step.Seconds <= 0 {
+			return nil, fmt.Errorf("exercise %d (%s): seconds must be greater than 0", i+1, step.Title)
+		}
+	}
+
+	return steps, nil
+}
+
+// loadExercises reads the user exercise list, writing the defaults on first use.
+func loadExercises() ([]ExerciseStep, error) {
+	path, err := exerciseFilePath()
+	if err != nil {
+		return defaultExercises, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultExercises, saveExercises(defaultExercises)
+	}
+	if err != nil {
+		return defaultExercises, err
+	}
+
+	return parseExercises(data)
+}
+
+func saveExercises(steps []ExerciseStep) error {
+	path, err := exerciseFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(steps, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func showExerciseRoutine(w fyne.Window, steps []ExerciseStep) {
+	if len(steps) == 0 {
+		return
+	}
+
+	current := 0
+	remaining := steps[0].Seconds
+	finished := false
+	done := make(chan struct{})
+
+	counter := widget.NewLabel("")
+	title := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	title.SizeName = theme.SizeNameSubHeadingText
+
+	instruction := widget.NewLabel("")
+	instruction.Wrapping = fyne.TextWrapWord
+	instruction.Alignment = fyne.TextAlignCenter
+
+	image := canvas.NewImageFromResource(nil)
+	image.FillMode = canvas.ImageFillContain
+	imageCard := container.NewGridWrap(fyne.NewSize(200, 200), image)
+
+	timer := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Monospace: true})
+	timer.SizeName = theme.SizeNameHeadingText
+
+	progress := widget.NewProgressBar()
+
+	var routine dialog.Dialog
+
+	showStep := func() {
+		step := steps[current]
+
+		counter.SetText(fmt.Sprintf("Step %d of %d", current+1, len(steps)))
+		title.SetText(step.Title)
+		instruction.SetText(step.Instruction)
+		timer.SetText(formatRemaining(time.Duration(remaining) * time.Second))
+		progress.Max = float64(step.Seconds)
+		progress.SetValue(float64(step.Seconds - remaining))
+
+		if step.Image != "" {
+			// relative image paths are resolved next to the exercise file
+			imagePath := step.Image
+			if path, err := exerciseFilePath(); err == nil && !filepath.IsAbs(imagePath) {
+				imagePath = filepath.Join(filepath.Dir(path), imagePath)
+			}
+
+			image.File = imagePath
+			image.Resource = nil
+			imageCard.Show()
+		} else {
+			imageCard.Hide()
+		}
+		image.Refresh()
+	}
+
+	next := func() {
+		if current+1 >= len(steps) {
+			routine.Hide()
+			return
+		}
+
+		current++
+		remaining = steps[current].Seconds
+		showStep()
+	}
+
+	skipBtn := widget.NewButtonWithIcon("Next", theme.MediaSkipNextIcon(), next)
+
+	content := container.NewVBox(
+		counter,
+		title,
+		container.NewCenter(imageCard),
+		instruction,
+		vPadding(10),
+		timer,
+		progress,
+		container.NewCenter(skipBtn),
+	)
+
+	routine = dialog.NewCustom("Break Exercises", "Finish", container.NewGridWrap(fyne.NewSize(420, 420), content), w)
+	routine.SetOnClosed(func() {
+		if !finished {
+			finished = true
+			close(done)
+		}
+	})
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if finished {
+						return
+					}
+
+					remaining--
+					if remaining <= 0 {
+						next()
+						return
+					}
+					showStep()
+				})
+			}
+		}
+	}()
+
+	showStep()
+	routine.Show()
+}
+
+func showExerciseEditor(w fyne.Window) {
+	path, err := exerciseFilePath()
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	var data []byte
+	steps, err := loadExercises()
+	if err != nil {
+		// a broken file is opened as written so saving can't drop the user's list
+		raw, readErr := os.ReadFile(path)
+		if readErr != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		data = raw
+	} else {
+		data, _ = json.MarshalIndent(steps, "", "  ")
+	}
+
+	editor := widget.NewMultiLineEntry()
+	editor.SetText(string(data))
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+
+	pathLabel := widget.NewLabel(path)
+	pathLabel.Wrapping = fyne.TextWrapBreak
+
+	content := container.NewBorder(pathLabel, nil, nil, nil, container.NewVScroll(editor))
+
+	editorDialog := dialog.NewCustomConfirm("Exercise List", "Save", "Cancel", content, func(save bool) {
+		if !save {
+			return
+		}
+
+		steps, err := parseExercises([]byte(editor.Text))
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		if err := saveExercises(steps); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		dialog.ShowInformation("Saved", "Exercise list has been saved.", w)
+	}, w)
+	editorDialog.Resize(fyne.NewSize(600, 500))
+	editorDialog.Show()
+
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("exercise list is invalid, fix it before saving: %w", err), w)
+	}
+}
+
+func startBreakExercises(w fyne.Window) {
+	if !exerciseDuringBreak {
+		return
+	}
+
+	steps, err := loadExercises()
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+
+	w.Show()
+	showExerciseRoutine(w, steps)
+}
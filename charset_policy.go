@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const ambiguousChars = "0OoIl1|`'\""
+
+const policyMaxAttempts = 1000
+
+var listCharClass = []string{"lower", "upper", "digit", "symbol"}
+
+type CharsetPolicy struct {
+	Lower, Upper, Digits, Symbols bool
+
+	// minimum count per class, keyed by listCharClass
+	Min map[string]int
+
+	ExcludeAmbiguous bool
+	Exclude          string
+
+	// Custom replaces the class checkboxes when not empty
+	Custom string
+
+	NoRepeat     bool
+	NoSequential bool
+}
+
+func charClass(c byte) string {
+	switch r := rune(c); {
+	case unicode.IsLower(r):
+		return "lower"
+	case unicode.IsUpper(r):
+		return "upper"
+	case unicode.IsDigit(r):
+		return "digit"
+	}
+
+	return "symbol"
+}
+
+// classes returns the allowed characters of every class after exclusions.
+func (p CharsetPolicy) classes() map[string]string {
+	source := p.Custom
+	if source == "" {
+		source = buildCharset(p.Lower, p.Upper, p.Digits, p.Symbols)
+	}
+
+	exclude := p.Exclude
+	if p.ExcludeAmbiguous {
+		exclude += ambiguousChars
+	}
+
+	seen := map[byte]bool{}
+	classes := map[string]string{}
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		if seen[c] || c < 0x20 || c > 0x7e || strings.IndexByte(exclude, c) >= 0 {
+			continue
+		}
+
+		seen[c] = true
+		classes[charClass(c)] += string(c)
+	}
+
+	return classes
+}
+
+// Charset is the union of all allowed characters.
+func (p CharsetPolicy) Charset() string {
+	classes := p.classes()
+
+	var b strings.Builder
+	for _, class := range listCharClass {
+		b.WriteString(classes[class])
+	}
+
+	return b.String()
+}
+
+func (p CharsetPolicy) Validate(length int) error {
+	// classes() works on bytes, so anything outside printable ASCII is refused
+	// here rather than silently dropped
+	for _, r := range p.Custom {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("custom charset only takes printable ASCII, %q is not", r)
+		}
+	}
+
+	classes := p.classes()
+	if p.Charset() == "" {
+		return errors.New("charset is empty, choose a class or check the exclusions")
+	}
+
+	total := 0
+	for _, class := range listCharClass {
+		min := p.Min[class]
+		if min < 0 {
+			return fmt.Errorf("minimum %s count can not be negative", class)
+		}
+		if min > 0 && classes[class] == "" {
+			return fmt.Errorf("minimum %d %s characters required but the charset has none", min, class)
+		}
+		total += min
+	}
+
+	if total > length {
+		return fmt.Errorf("minimum counts add up to %d, more than the length %d", total, length)
+	}
+
+	return nil
+}
+
+// conflicts reports whether c may not follow prev under the repeat/sequence rules.
+func (p CharsetPolicy) conflicts(prev, prev2 byte, pos int, c byte) bool {
+	if pos == 0 {
+		return false
+	}
+	if p.NoRepeat && c == prev {
+		return true
+	}
+	if p.NoSequential && pos >= 2 {
+		d1 := int(prev) - int(prev2)
+		d2 := int(c) - int(prev)
+		if (d1 == 1 || d1 == -1) && d2 == d1 && charClass(c) == charClass(prev) && charClass(prev) == charClass(prev2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pickFrom chooses uniformly among the characters of set allowed at pos.
+func (p CharsetPolicy) pickFrom(set string, out []byte, pos int) (byte, error) {
+	var prev, prev2 byte
+	if pos > 0 {
+		prev = out[pos-1]
+	}
+	if pos > 1 {
+		prev2 = out[pos-2]
+	}
+
+	candidates := make([]byte, 0, len(set))
+	for i := 0; i < len(set); i++ {
+		if !p.conflicts(prev, prev2, pos, set[i]) {
+			candidates = append(candidates, set[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, errors.New("no character satisfies the repeat/sequence rules")
+	}
+
+	n, err := randomInt(len(candidates))
+	if err != nil {
+		return 0, err
+	}
+
+	return candidates[n], nil
+}
+
+func (p CharsetPolicy) satisfiesMin(out []byte) bool {
+	counts := map[string]int{}
+	for _, c := range out {
+		counts[charClass(c)]++
+	}
+
+	for _, class := range listCharClass {
+		if counts[class] < p.Min[class] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GenerateWithPolicy draws every character with randomInt. It first uses
+// rejection sampling, which keeps the output uniform over all valid strings,
+// and falls back to placing the required classes at random positions when the
+// policy is too strict for rejection to succeed quickly.
+func GenerateWithPolicy(p CharsetPolicy, length int) (string, error) {
+	if length <= 0 {
+		return "", nil
+	}
+	if err := p.Validate(length); err != nil {
+		return "", err
+	}
+
+	charset := p.Charset()
+	classes := p.classes()
+	out := make([]byte, length)
+
+	for attempt := 0; attempt < policyMaxAttempts; attempt++ {
+		ok := true
+		for i := range out {
+			n, err := randomInt(len(charset))
+			if err != nil {
+				return "", err
+			}
+
+			out[i] = charset[n]
+			if i > 0 && p.conflicts(out[i-1], out[max(i-2, 0)], i, out[i]) {
+				ok = false
+				break
+			}
+		}
+
+		if ok && p.satisfiesMin(out) {
+			return string(out), nil
+		}
+	}
+
+	for attempt := 0; attempt < policyMaxAttempts; attempt++ {
+		slots := make([]string, 0, length)
+		for _, class := range listCharClass {
+			for i := 0; i < p.Min[class]; i++ {
+				slots = append(slots, classes[class])
+			}
+		}
+		for len(slots) < length {
+			slots = append(slots, charset)
+		}
+
+		// Fisher-Yates shuffle of the slot classes
+		for i := len(slots) - 1; i > 0; i-- {
+			j, err := randomInt(i + 1)
+			if err != nil {
+				return "", err
+			}
+			slots[i], slots[j] = slots[j], slots[i]
+		}
+
+		ok := true
+		for i := range out {
+			c, err := p.pickFrom(slots[i], out, i)
+			if err != nil {
+				ok = false
+				break
+			}
+			out[i] = c
+		}
+
+		if ok {
+			return string(out), nil
+		}
+	}
+
+	return "", errors.New("could not satisfy the charset policy, relax the minimum counts or the repeat/sequence rules")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// clipboardClearAfter is how long a copied secret stays in the clipboard, 0 keeps it.
+var clipboardClearAfter = 30 * time.Second
+
+// clipboardGeneration makes an older timer skip clearing once something newer was copied.
+var clipboardGeneration int
+
+// copySecret copies text and clears the clipboard after clipboardClearAfter,
+// unless the clipboard no longer holds text by then.
+func copySecret(w fyne.Window, text string) {
+	w.Clipboard().SetContent(text)
+
+	clipboardGeneration++
+	if clipboardClearAfter <= 0 {
+		return
+	}
+
+	generation := clipboardGeneration
+	time.AfterFunc(clipboardClearAfter, func() {
+		fyne.Do(func() {
+			if generation != clipboardGeneration || w.Clipboard().Content() != text {
+				return
+			}
+
+			w.Clipboard().SetContent("")
+		})
+	})
+}
+
+func maskSecret(text string) string {
+	return strings.Repeat("•", min(len([]rune(text)), 24))
+}
+
+var errImageClipboard = errors.New("copying images is not supported here, install wl-clipboard or xclip on Linux, or save the file instead")
+
+// copyImage puts a PNG on the system clipboard. Fyne's clipboard is text only,
+// so this hands the image to the platform's own clipboard tool.
+func copyImage(pngData []byte) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("wl-copy"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+			cmd = exec.Command("wl-copy", "--type", "image/png")
+		} else if _, err := exec.LookPath("xclip"); err == nil {
+			cmd = exec.Command("xclip", "-selection", "clipboard", "-t", "image/png", "-i")
+		} else {
+			return errImageClipboard
+		}
+		cmd.Stdin = bytes.NewReader(pngData)
+	case "darwin", "windows":
+		// both tools want a file rather than stdin
+		file, err := os.CreateTemp("", "qr-*.png")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+
+		_, err = file.Write(pngData)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+
+		if runtime.GOOS == "darwin" {
+			cmd = exec.Command("osascript", "-e", `set the clipboard to (read (POSIX file "`+file.Name()+`") as «class PNGf»)`)
+		} else {
+			cmd = exec.Command("powershell", "-NoProfile", "-STA", "-Command",
+				`Add-Type -AssemblyName System.Windows.Forms, System.Drawing; [System.Windows.Forms.Clipboard]::SetImage([System.Drawing.Image]::FromFile('`+file.Name()+`'))`)
+		}
+	default:
+		return errImageClipboard
+	}
+
+	// no output capture: wl-copy and xclip fork a process that keeps serving the
+	// clipboard, and it would hold our pipes open
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("copy image failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"math/big"
+	"mime/quotedprintable"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type Codec struct {
+	Name   string
+	Encode func([]byte) ([]byte, error)
+	Decode func([]byte) ([]byte, error)
+}
+
+type CodecStep struct {
+	Codec  string
+	Decode bool
+}
+
+func (s CodecStep) String() string {
+	if s.Decode {
+		return s.Codec + " decode"
+	}
+
+	return s.Codec + " encode"
+}
+
+var listCodecs = []Codec{
+	{Name: "Base64", Encode: encodeWith(base64.StdEncoding.EncodeToString), Decode: decodeBase64Loose(base64.RawStdEncoding)},
+	{Name: "Base64 URL", Encode: encodeWith(base64.RawURLEncoding.EncodeToString), Decode: decodeBase64Loose(base64.RawURLEncoding)},
+	{Name: "Base32", Encode: encodeWith(base32.StdEncoding.EncodeToString), Decode: decodeBase32},
+	{Name: "Base58", Encode: encodeWith(encodeBase58), Decode: decodeBase58},
+	{Name: "Ascii85", Encode: encodeAscii85, Decode: decodeAscii85},
+	{Name: "Hex", Encode: encodeWith(hex.EncodeToString), Decode: decodeHex},
+	{Name: "URL Percent", Encode: encodeWith(percentEncode), Decode: decodePercent},
+	{Name: "HTML Entities", Encode: encodeWith(func(b []byte) string { return html.EscapeString(string(b)) }), Decode: decodeHTML},
+	{Name: "Quoted-Printable", Encode: encodeQuotedPrintable, Decode: decodeQuotedPrintable},
+	{Name: "Gzip", Encode: compressWith(func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }), Decode: decompressWith(func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) })},
+	{Name: "Deflate", Encode: compressWith(func(w io.Writer) (io.WriteCloser, error) { return flate.NewWriter(w, flate.DefaultCompression) }), Decode: decompressWith(func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil })},
+	{Name: "Zlib", Encode: compressWith(func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil }), Decode: decompressWith(zlib.NewReader)},
+	{Name: "UTF-16LE", Encode: encodeUTF16(binary.LittleEndian), Decode: decodeUTF16(binary.LittleEndian)},
+	{Name: "UTF-16BE", Encode: encodeUTF16(binary.BigEndian), Decode: decodeUTF16(binary.BigEndian)},
+}
+
+func codecByName(name string) (Codec, bool) {
+	for _, codec := range listCodecs {
+		if codec.Name == name {
+			return codec, true
+		}
+	}
+
+	return Codec{}, false
+}
+
+func codecNames() []string {
+	names := make([]string, 0, len(listCodecs))
+	for _, codec := range listCodecs {
+		names = append(names, codec.Name)
+	}
+
+	return names
+}
+
+// runCodecChain applies every step in order and returns each intermediate result,
+// when a step fails the results before it are still returned.
+func runCodecChain(input []byte, steps []CodecStep) ([][]byte, error) {
+	results := make([][]byte, 0, len(steps))
+	current := input
+
+	for i, step := range steps {
+		codec, ok := codecByName(step.Codec)
+		if !ok {
+			return results, fmt.Errorf("step %d: unknown codec %q", i+1, step.Codec)
+		}
+
+		var err error
+		if step.Decode {
+			current, err = codec.Decode(current)
+		} else {
+			current, err = codec.Encode(current)
+		}
+		if err != nil {
+			return results, fmt.Errorf("step %d (%s): %w", i+1, step, err)
+		}
+
+		results = append(results, current)
+	}
+
+	return results, nil
+}
+
+func encodeWith(fn func([]byte) string) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		return []byte(fn(b)), nil
+	}
+}
+
+func stripSpaces(b []byte) string {
+	return strings.Join(strings.Fields(string(b)), "")
+}
+
+// decodeBase64Loose accepts both padded and unpadded input.
+func decodeBase64Loose(enc *base64.Encoding) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		return enc.DecodeString(strings.TrimRight(stripSpaces(b), "="))
+	}
+}
+
+func decodeBase32(b []byte) ([]byte, error) {
+	text := strings.ToUpper(strings.TrimRight(stripSpaces(b), "="))
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(text)
+}
+
+func decodeHex(b []byte) ([]byte, error) {
+	text := strings.TrimPrefix(stripSpaces(b), "0x")
+	text = strings.NewReplacer(":", "", "-", "").Replace(text)
+
+	return hex.DecodeString(text)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// encodeBase58 uses the Bitcoin alphabet, leading zero bytes become leading '1'.
+func encodeBase58(b []byte) string {
+	num := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for num.Sign() > 0 {
+		num.DivMod(num, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+func decodeBase58(b []byte) ([]byte, error) {
+	text := stripSpaces(b)
+	num := new(big.Int)
+	radix := big.NewInt(58)
+
+	for i := 0; i < len(text); i++ {
+		idx := strings.IndexByte(base58Alphabet, text[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at offset %d", text[i], i)
+		}
+
+		num.Mul(num, radix)
+		num.Add(num, big.NewInt(int64(idx)))
+	}
+
+	leading := 0
+	for leading < len(text) && text[leading] == base58Alphabet[0] {
+		leading++
+	}
+
+	return append(make([]byte, leading), num.Bytes()...), nil
+}
+
+func encodeAscii85(b []byte) ([]byte, error) {
+	out := make([]byte, ascii85.MaxEncodedLen(len(b)))
+	n := ascii85.Encode(out, b)
+
+	return out[:n], nil
+}
+
+func decodeAscii85(b []byte) ([]byte, error) {
+	text := stripSpaces(b)
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "<~"), "~>")
+
+	out := make([]byte, 4*len(text))
+	n, _, err := ascii85.Decode(out, []byte(text), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return out[:n], nil
+}
+
+// percentEncode escapes everything except the RFC 3986 unreserved characters.
+func percentEncode(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			sb.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", c)
+	}
+
+	return sb.String()
+}
+
+func decodePercent(b []byte) ([]byte, error) {
+	text, err := url.PathUnescape(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(text), nil
+}
+
+func decodeHTML(b []byte) ([]byte, error) {
+	return []byte(html.UnescapeString(string(b))), nil
+}
+
+func encodeQuotedPrintable(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write(b); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeQuotedPrintable(b []byte) ([]byte, error) {
+	return io.ReadAll(quotedprintable.NewReader(bytes.NewReader(b)))
+}
+
+func compressWith(newWriter func(io.Writer) (io.WriteCloser, error)) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		var buf bytes.Buffer
+		writer, err := newWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(b); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+}
+
+func decompressWith(newReader func(io.Reader) (io.ReadCloser, error)) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		reader, err := newReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		out, err := io.ReadAll(io.LimitReader(reader, decompressLimit+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > decompressLimit {
+			return nil, fmt.Errorf("decompressed data is larger than %d MB", decompressLimit/1024/1024)
+		}
+
+		return out, nil
+	}
+}
+
+func encodeUTF16(order binary.ByteOrder) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("input is not valid UTF-8 text")
+		}
+
+		units := utf16.Encode([]rune(string(b)))
+		out := make([]byte, 2*len(units))
+		for i, u := range units {
+			order.PutUint16(out[2*i:], u)
+		}
+
+		return out, nil
+	}
+}
+
+func decodeUTF16(order binary.ByteOrder) func([]byte) ([]byte, error) {
+	return func(b []byte) ([]byte, error) {
+		if len(b)%2 != 0 {
+			return nil, fmt.Errorf("UTF-16 input must have an even number of bytes, got %d", len(b))
+		}
+
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = order.Uint16(b[2*i:])
+		}
+
+		// drop the byte order mark if present
+		if len(units) > 0 && units[0] == 0xfeff {
+			units = units[1:]
+		}
+
+		return []byte(string(utf16.Decode(units))), nil
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var listExportFormat = []string{"TXT", "CSV", "JSON", ".env"}
+
+// exportAsGenerated writes lines verbatim, for modes whose output already is a
+// document (Fake Data CSV, JSON Lines and SQL). It isn't offered in the select.
+const exportAsGenerated = "As Generated"
+
+// exportBatchSize bounds how many values a streamed export holds in memory at once.
+const exportBatchSize = 10000
+
+func exportExtension(format string) string {
+	switch format {
+	case "CSV":
+		return ".csv"
+	case "JSON":
+		return ".json"
+	case ".env":
+		return ".env"
+	}
+
+	return ".txt"
+}
+
+// envKeyPrefix turns a user prefix into a valid variable name, "api key" becomes "API_KEY".
+func envKeyPrefix(prefix string) (string, error) {
+	var sb strings.Builder
+	for _, r := range strings.TrimSpace(prefix) {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(unicode.ToUpper(r))
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+
+	key := sb.String()
+	if key == "" {
+		return "", errors.New("key prefix must be fill")
+	}
+	if unicode.IsDigit(rune(key[0])) {
+		return "", fmt.Errorf("key prefix %q can not start with a digit", key)
+	}
+
+	return key, nil
+}
+
+// envValue quotes a value only when a .env parser would otherwise misread it.
+func envValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t#\"'`$\\=") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// ExportWriter writes values one batch at a time, so the output never has
+// to be built as a single string.
+type ExportWriter struct {
+	out    *bufio.Writer
+	csv    *csv.Writer
+	format string
+	prefix string
+	count  int
+}
+
+func NewExportWriter(dst io.Writer, format, prefix string) (*ExportWriter, error) {
+	e := &ExportWriter{out: bufio.NewWriter(dst), format: format}
+
+	switch format {
+	case "TXT", exportAsGenerated:
+	case "CSV":
+		e.csv = csv.NewWriter(e.out)
+		if err := e.csv.Write([]string{"value"}); err != nil {
+			return nil, err
+		}
+	case "JSON":
+		e.out.WriteString("[")
+	case ".env":
+		key, err := envKeyPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		e.prefix = key
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	return e, nil
+}
+
+func (e *ExportWriter) Write(values []string) error {
+	for _, value := range values {
+		e.count++
+
+		switch e.format {
+		case "TXT", exportAsGenerated:
+			e.out.WriteString(value)
+			e.out.WriteByte('\n')
+		case "CSV":
+			if err := e.csv.Write([]string{value}); err != nil {
+				return err
+			}
+		case "JSON":
+			quoted, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if e.count > 1 {
+				e.out.WriteByte(',')
+			}
+			e.out.WriteString("\n  ")
+			e.out.Write(quoted)
+		case ".env":
+			fmt.Fprintf(e.out, "%s_%d=%s\n", e.prefix, e.count, envValue(value))
+		}
+	}
+
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+
+	return nil
+}
+
+// Close finishes the document and flushes, it does not close the destination.
+func (e *ExportWriter) Close() error {
+	if e.format == "JSON" {
+		e.out.WriteString("\n]\n")
+	}
+
+	return e.out.Flush()
+}
+
+// StreamExport generates count values in batches and writes them as they
+// come. Every batch starts with headerLines lines of header (Fake Data CSV),
+// kept for the first batch only.
+func StreamExport(ctx context.Context, dst io.Writer, format, prefix string, count, headerLines int,
+	generate func(count int) ([]string, string, error), progress func(done int)) error {
+	e, err := NewExportWriter(dst, format, prefix)
+	if err != nil {
+		return err
+	}
+
+	for done := 0; done < count; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n := min(exportBatchSize, count-done)
+		values, _, err := generate(n)
+		if err != nil {
+			return err
+		}
+		if done > 0 {
+			values = values[min(headerLines, len(values)):]
+		}
+
+		if err := e.Write(values); err != nil {
+			return err
+		}
+
+		done += n
+		progress(done)
+	}
+
+	return e.Close()
+}
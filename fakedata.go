@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var listFakeField = []string{"Name", "Email", "Phone", "Address", "IBAN", "Date", "Lorem"}
+
+var listFakeFormat = []string{"CSV", "JSON Lines", "SQL INSERT"}
+
+// fakeFormatExport is the file extension of a fake data format and how many
+// header lines FormatFakeRows puts before the rows.
+func fakeFormatExport(format string) (string, int) {
+	switch format {
+	case "JSON Lines":
+		return ".jsonl", 0
+	case "SQL INSERT":
+		return ".sql", 0
+	}
+
+	return ".csv", 1
+}
+
+// FakeLocale holds the word pools and masks of one locale pack. Phone and
+// Postcode use the mask syntax of the Template mode, Address is a format
+// taking the house number, street, city and postcode in that order.
+type FakeLocale struct {
+	Name        string
+	FirstNames  []string
+	LastNames   []string
+	Streets     []string
+	Address     string
+	Cities      []string
+	Domains     []string
+	Phone       string
+	Postcode    string
+	IBANCountry string
+	BBANLength  int
+}
+
+var listFakeLocale = []FakeLocale{
+	{
+		Name:        "English (US)",
+		FirstNames:  []string{"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Daniel", "Karen", "Emily", "Andrew", "Olivia", "Ethan"},
+		LastNames:   []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Wilson", "Anderson", "Taylor", "Thomas", "Moore", "Jackson", "Martin", "Lee", "Thompson", "White", "Harris", "Clark"},
+		Streets:     []string{"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Washington", "Lake", "Hill", "Park", "Sunset", "Highland", "River", "Church"},
+		Address:     "%[1]d %[2]s St., %[3]s %[4]s",
+		Cities:      []string{"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton", "Fairview", "Salem", "Madison", "Georgetown", "Arlington", "Ashland"},
+		Domains:     []string{"example.com", "example.org", "example.net", "mail.test"},
+		Phone:       "+1 (###) ###-####",
+		Postcode:    "#####",
+		IBANCountry: "GB",
+		BBANLength:  18,
+	},
+	{
+		Name:        "Indonesian",
+		FirstNames:  []string{"Budi", "Siti", "Agus", "Dewi", "Andi", "Rina", "Joko", "Sri", "Bambang", "Wulan", "Hendra", "Ayu", "Rizky", "Putri", "Eko", "Indah", "Fajar", "Nur", "Yusuf", "Ratna", "Dimas", "Kartika", "Arief", "Lestari"},
+		LastNames:   []string{"Santoso", "Wijaya", "Saputra", "Kusuma", "Hidayat", "Pratama", "Setiawan", "Nugroho", "Susanto", "Halim", "Gunawan", "Siregar", "Nasution", "Simanjuntak", "Harahap", "Wibowo", "Purnomo", "Rahmawati", "Utami", "Permana"},
+		Streets:     []string{"Merdeka", "Sudirman", "Thamrin", "Diponegoro", "Gatot Subroto", "Ahmad Yani", "Pahlawan", "Kartini", "Gajah Mada", "Hayam Wuruk", "Pemuda", "Veteran", "Cendrawasih", "Melati"},
+		Address:     "Jl. %[2]s No. %[1]d, %[3]s %[4]s",
+		Cities:      []string{"Jakarta", "Surabaya", "Bandung", "Medan", "Semarang", "Makassar", "Palembang", "Yogyakarta", "Denpasar", "Malang", "Bogor", "Balikpapan"},
+		Domains:     []string{"contoh.co.id", "contoh.id", "surel.test", "example.com"},
+		Phone:       "+62 8##-####-####",
+		Postcode:    "#####",
+		IBANCountry: "ID",
+		BBANLength:  16,
+	},
+	{
+		Name:        "German",
+		FirstNames:  []string{"Lukas", "Anna", "Leon", "Lena", "Finn", "Marie", "Jonas", "Sophie", "Paul", "Laura", "Felix", "Julia", "Maximilian", "Hannah", "Elias", "Lea", "Noah", "Emma", "Ben", "Mia"},
+		LastNames:   []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann", "Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf", "Neumann", "Schwarz"},
+		Streets:     []string{"Haupt", "Schul", "Garten", "Bahnhof", "Dorf", "Berg", "Linden", "Kirch", "Wald", "Ring", "Birken", "Wiesen"},
+		Address:     "%[2]sstraße %[1]d, %[4]s %[3]s",
+		Cities:      []string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt", "Stuttgart", "Düsseldorf", "Leipzig", "Dortmund", "Bremen", "Dresden", "Hannover"},
+		Domains:     []string{"beispiel.de", "example.com", "post.test"},
+		Phone:       "+49 1## #######",
+		Postcode:    "#####",
+		IBANCountry: "DE",
+		BBANLength:  18,
+	},
+}
+
+var loremWords = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud exercitation ullamco laboris nisi aliquip ex ea commodo consequat duis aute irure in reprehenderit voluptate velit esse cillum fugiat nulla pariatur excepteur sint occaecat cupidatat non proident sunt culpa qui officia deserunt mollit anim id est laborum")
+
+func fakeLocaleByName(name string) (FakeLocale, bool) {
+	for _, locale := range listFakeLocale {
+		if locale.Name == name {
+			return locale, true
+		}
+	}
+
+	return FakeLocale{}, false
+}
+
+func pickWord(words []string) (string, error) {
+	n, err := randomInt(len(words))
+	if err != nil {
+		return "", err
+	}
+
+	return words[n], nil
+}
+
+func fillMask(mask string) (string, error) {
+	parts, err := compileMask(mask)
+	if err != nil {
+		return "", err
+	}
+
+	return generateMask(parts)
+}
+
+// emailLocalPart folds a name to the ASCII letters an address can hold, so
+// "Müller" becomes "muller".
+func emailLocalPart(name string) string {
+	replacer := strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss", "Ä", "a", "Ö", "o", "Ü", "u")
+
+	var sb strings.Builder
+	for _, r := range replacer.Replace(name) {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return sb.String()
+}
+
+// fakeIBAN builds an IBAN-shaped number whose check digits pass the mod 97
+// test. Countries without IBANs still get one, hence "IBAN-like".
+func fakeIBAN(country string, bbanLength int) (string, error) {
+	bban, err := fillMask(strings.Repeat("#", bbanLength))
+	if err != nil {
+		return "", err
+	}
+
+	var digits strings.Builder
+	for _, r := range bban + country + "00" {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(fmt.Sprint(r - 'A' + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	num, _ := new(big.Int).SetString(digits.String(), 10)
+	check := 98 - new(big.Int).Mod(num, big.NewInt(97)).Int64()
+	iban := fmt.Sprintf("%s%02d%s", country, check, bban)
+
+	var grouped []string
+	for i := 0; i < len(iban); i += 4 {
+		grouped = append(grouped, iban[i:min(i+4, len(iban))])
+	}
+
+	return strings.Join(grouped, " "), nil
+}
+
+func fakeDate(from, to time.Time) (string, error) {
+	days := int(to.Sub(from).Hours() / 24)
+	n, err := randomInt(days + 1)
+	if err != nil {
+		return "", err
+	}
+
+	return from.AddDate(0, 0, n).Format("2006-01-02"), nil
+}
+
+func fakeLorem(minWords, maxWords int) (string, error) {
+	n, err := randomInt(maxWords - minWords + 1)
+	if err != nil {
+		return "", err
+	}
+
+	words := make([]string, minWords+n)
+	for i := range words {
+		if words[i], err = pickWord(loremWords); err != nil {
+			return "", err
+		}
+	}
+
+	return capitalizeWord(strings.Join(words, " ")) + ".", nil
+}
+
+// FakeRecord generates one row with a value for every field, in order. The
+// email is derived from the name so the two stay consistent within a row.
+func FakeRecord(locale FakeLocale, fields []string) ([]string, error) {
+	first, err := pickWord(locale.FirstNames)
+	if err != nil {
+		return nil, err
+	}
+	last, err := pickWord(locale.LastNames)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var value string
+
+		switch field {
+		case "Name":
+			value = first + " " + last
+		case "Email":
+			domain, err := pickWord(locale.Domains)
+			if err != nil {
+				return nil, err
+			}
+			suffix, err := fillMask("##")
+			if err != nil {
+				return nil, err
+			}
+			value = emailLocalPart(first) + "." + emailLocalPart(last) + suffix + "@" + domain
+		case "Phone":
+			value, err = fillMask(locale.Phone)
+		case "Address":
+			value, err = fakeAddress(locale)
+		case "IBAN":
+			value, err = fakeIBAN(locale.IBANCountry, locale.BBANLength)
+		case "Date":
+			value, err = fakeDate(time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC), randomClock())
+		case "Lorem":
+			value, err = fakeLorem(6, 14)
+		default:
+			err = fmt.Errorf("unknown field %q", field)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, value)
+	}
+
+	return row, nil
+}
+
+func fakeAddress(locale FakeLocale) (string, error) {
+	street, err := pickWord(locale.Streets)
+	if err != nil {
+		return "", err
+	}
+	city, err := pickWord(locale.Cities)
+	if err != nil {
+		return "", err
+	}
+	number, err := randomInt(200)
+	if err != nil {
+		return "", err
+	}
+	postcode, err := fillMask(locale.Postcode)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(locale.Address, number+1, street, city, postcode), nil
+}
+
+// columnName turns a field label into a lowercase identifier for JSON keys and SQL columns.
+func columnName(field string) string {
+	return strings.ToLower(strings.ReplaceAll(field, " ", "_"))
+}
+
+// sqlIdentifier is what a table name may be, it is written into the statement unquoted.
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// FormatFakeRows renders rows as CSV (with a header), JSON lines or SQL INSERT statements.
+func FormatFakeRows(format string, table string, fields []string, rows [][]string) ([]string, error) {
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = columnName(field)
+	}
+
+	switch format {
+	case "CSV":
+		var sb strings.Builder
+		writer := csv.NewWriter(&sb)
+		writer.Write(columns)
+		writer.WriteAll(rows)
+		if err := writer.Error(); err != nil {
+			return nil, err
+		}
+
+		return strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n"), nil
+	case "JSON Lines":
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			// built by hand so the keys keep the field order
+			pairs := make([]string, len(row))
+			for i, value := range row {
+				key, _ := json.Marshal(columns[i])
+				val, _ := json.Marshal(value)
+				pairs[i] = string(key) + ":" + string(val)
+			}
+			lines = append(lines, "{"+strings.Join(pairs, ",")+"}")
+		}
+
+		return lines, nil
+	case "SQL INSERT":
+		if table == "" {
+			return nil, errors.New("table name must be fill")
+		}
+		if !sqlIdentifier.MatchString(table) {
+			return nil, fmt.Errorf("invalid table name: %v, use letters, digits and underscores", table)
+		}
+
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = sqlQuote(value)
+			}
+			lines = append(lines, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", table, strings.Join(columns, ", "), strings.Join(values, ", ")))
+		}
+
+		return lines, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/scrypt"
+)
+
+var listHashAlgorithm = []string{"MD5", "SHA-1", "SHA-224", "SHA-256", "SHA-384", "SHA-512", "SHA3-256", "SHA3-512", "BLAKE2b-256", "BLAKE2b-512", "BLAKE2s-256"}
+
+var listDigestEncoding = []string{"Hex", "Base64"}
+
+var listPasswordHash = []string{"bcrypt", "scrypt", "argon2id"}
+
+// PasswordHashParams holds the cost knobs of every password hash, only the
+// ones of the chosen algorithm are used.
+type PasswordHashParams struct {
+	BcryptCost int
+
+	// scrypt N is 2^ScryptLogN
+	ScryptLogN int
+	ScryptR    int
+	ScryptP    int
+
+	// argon2id memory is in KiB
+	ArgonTime    uint32
+	ArgonMemory  uint32
+	ArgonThreads uint8
+}
+
+var defaultPasswordHashParams = PasswordHashParams{
+	BcryptCost:   bcrypt.DefaultCost,
+	ScryptLogN:   15,
+	ScryptR:      8,
+	ScryptP:      1,
+	ArgonTime:    3,
+	ArgonMemory:  64 * 1024,
+	ArgonThreads: 4,
+}
+
+const passwordSaltSize = 16
+const passwordKeySize = 32
+
+// Upper bounds for the KDF parameters. They apply to pasted hashes too, a
+// huge value would otherwise allocate until the app is killed.
+const (
+	scryptMaxLogN   = 20
+	scryptMaxRP     = 256
+	kdfMaxMemory    = 1 << 30 // bytes
+	argonMaxTime    = 64
+	passwordMaxHash = 128 // bytes, for verifying
+)
+
+func hashConstructor(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "MD5":
+		return md5.New, nil
+	case "SHA-1":
+		return sha1.New, nil
+	case "SHA-224":
+		return sha256.New224, nil
+	case "SHA-256":
+		return sha256.New, nil
+	case "SHA-384":
+		return sha512.New384, nil
+	case "SHA-512":
+		return sha512.New, nil
+	case "SHA3-256":
+		return func() hash.Hash { return sha3.New256() }, nil
+	case "SHA3-512":
+		return func() hash.Hash { return sha3.New512() }, nil
+	case "BLAKE2b-256":
+		return func() hash.Hash {
+			h, _ := blake2b.New256(nil)
+			return h
+		}, nil
+	case "BLAKE2b-512":
+		return func() hash.Hash {
+			h, _ := blake2b.New512(nil)
+			return h
+		}, nil
+	case "BLAKE2s-256":
+		return func() hash.Hash {
+			h, _ := blake2s.New256(nil)
+			return h
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown hash algorithm %q", algorithm)
+}
+
+// Digest hashes data, or computes an HMAC over it when key is not empty.
+func Digest(algorithm string, data, key []byte, encoding string) (string, error) {
+	newHash, err := hashConstructor(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(newHash, key)
+	} else {
+		h = newHash()
+	}
+	h.Write(data)
+	sum := h.Sum(nil)
+
+	if encoding == "Base64" {
+		return base64.StdEncoding.EncodeToString(sum), nil
+	}
+
+	return hex.EncodeToString(sum), nil
+}
+
+// HashPassword returns bcrypt's own "$2a$" format, and PHC strings
+// ("$argon2id$v=19$m=...", "$scrypt$ln=...") for the other two.
+func HashPassword(algorithm, password string, params PasswordHashParams) (string, error) {
+	switch algorithm {
+	case "bcrypt":
+		if len(password) > 72 {
+			return "", errors.New("bcrypt only uses the first 72 bytes, the password is longer")
+		}
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return "", fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+
+		out, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+		return string(out), err
+	case "scrypt":
+		salt, err := passwordSalt()
+		if err != nil {
+			return "", err
+		}
+
+		key, err := scryptKey(password, salt, params.ScryptLogN, params.ScryptR, params.ScryptP, passwordKeySize)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", params.ScryptLogN, params.ScryptR, params.ScryptP,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	case "argon2id":
+		if err := checkArgon2Params(params.ArgonTime, params.ArgonMemory, params.ArgonThreads); err != nil {
+			return "", err
+		}
+
+		salt, err := passwordSalt()
+		if err != nil {
+			return "", err
+		}
+
+		key := argon2.IDKey([]byte(password), salt, params.ArgonTime, params.ArgonMemory, params.ArgonThreads, passwordKeySize)
+
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.ArgonMemory, params.ArgonTime, params.ArgonThreads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+
+	return "", fmt.Errorf("unknown password hash %q", algorithm)
+}
+
+// passwordSalt reads crypto/rand directly, hashing runs off the UI goroutine.
+func passwordSalt() ([]byte, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+// checkScryptParams keeps scrypt.Key from dividing by zero, shifting by a
+// negative amount or allocating more than kdfMaxMemory (128·r·N bytes).
+func checkScryptParams(logN, r, p int) error {
+	if logN < 1 || logN > scryptMaxLogN {
+		return fmt.Errorf("scrypt log2(N) must be between 1 and %d", scryptMaxLogN)
+	}
+	// r and p are checked alone first so a pasted huge value can't overflow r·p
+	if r < 1 || p < 1 || r > scryptMaxRP || p > scryptMaxRP || r*p > scryptMaxRP {
+		return fmt.Errorf("scrypt needs r ≥ 1, p ≥ 1 and r·p ≤ %d", scryptMaxRP)
+	}
+	if memory := int64(128*r) << logN; memory > kdfMaxMemory {
+		return fmt.Errorf("scrypt would use %d MiB, the limit is %d MiB, lower N or r", memory>>20, kdfMaxMemory>>20)
+	}
+
+	return nil
+}
+
+func checkArgon2Params(time, memory uint32, threads uint8) error {
+	if time < 1 || time > argonMaxTime {
+		return fmt.Errorf("argon2id time must be between 1 and %d", argonMaxTime)
+	}
+	if memory < 8*uint32(max(threads, 1)) || memory > kdfMaxMemory>>10 {
+		return fmt.Errorf("argon2id memory must be between 8 KiB per thread and %d KiB", kdfMaxMemory>>10)
+	}
+	if threads < 1 {
+		return errors.New("argon2id threads must be at least 1")
+	}
+
+	return nil
+}
+
+func checkHashLength(key []byte) error {
+	if len(key) < 4 || len(key) > passwordMaxHash {
+		return fmt.Errorf("hash must be between 4 and %d bytes, got %d", passwordMaxHash, len(key))
+	}
+
+	return nil
+}
+
+func scryptKey(password string, salt []byte, logN, r, p, keyLen int) ([]byte, error) {
+	if err := checkScryptParams(logN, r, p); err != nil {
+		return nil, err
+	}
+
+	return scrypt.Key([]byte(password), salt, 1<<logN, r, p, keyLen)
+}
+
+// splitPHC splits "$id$params$salt$hash" (argon2id also has a "v=19" field)
+// into its parameter string, salt and hash.
+func splitPHC(encoded string, fields int) ([]string, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != fields+1 {
+		return nil, nil, nil, errors.New("malformed hash, wrong number of '$' fields")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[len(parts)-2])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("malformed salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("malformed hash: %w", err)
+	}
+
+	return parts[2 : len(parts)-2], salt, key, nil
+}
+
+// VerifyPassword checks password against a bcrypt, scrypt or argon2id hash
+// and returns the detected algorithm.
+func VerifyPassword(password, encoded string) (string, bool, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return "bcrypt", false, nil
+		}
+
+		return "bcrypt", err == nil, err
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		params, salt, key, err := splitPHC(encoded, 4)
+		if err != nil {
+			return "scrypt", false, err
+		}
+
+		var logN, r, p int
+		if _, err := fmt.Sscanf(params[0], "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil {
+			return "scrypt", false, fmt.Errorf("malformed scrypt parameters %q", params[0])
+		}
+
+		if err := checkHashLength(key); err != nil {
+			return "scrypt", false, err
+		}
+
+		candidate, err := scryptKey(password, salt, logN, r, p, len(key))
+		if err != nil {
+			return "scrypt", false, err
+		}
+
+		return "scrypt", subtle.ConstantTimeCompare(candidate, key) == 1, nil
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := splitPHC(encoded, 5)
+		if err != nil {
+			return "argon2id", false, err
+		}
+
+		var version int
+		var memory, time uint32
+		var threads uint8
+		if _, err := fmt.Sscanf(params[0], "v=%d", &version); err != nil || version != argon2.Version {
+			return "argon2id", false, fmt.Errorf("unsupported argon2 version %q", params[0])
+		}
+		if _, err := fmt.Sscanf(params[1], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+			return "argon2id", false, fmt.Errorf("malformed argon2id parameters %q", params[1])
+		}
+
+		if err := checkArgon2Params(time, memory, threads); err != nil {
+			return "argon2id", false, err
+		}
+		if err := checkHashLength(key); err != nil {
+			return "argon2id", false, err
+		}
+
+		candidate := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+
+		return "argon2id", subtle.ConstantTimeCompare(candidate, key) == 1, nil
+	}
+
+	return "", false, errors.New("unrecognised hash, expected bcrypt ($2a$/$2b$/$2y$), scrypt ($scrypt$) or argon2id ($argon2id$)")
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var listIDType = []string{"UUID v4", "UUID v7", "ULID", "NanoID", "KSUID", "Snowflake"}
+
+const nanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// KSUID timestamps count seconds from 2014-05-13, Snowflake defaults to the Twitter epoch.
+const ksuidEpoch = 1400000000
+const defaultSnowflakeEpoch = 1288834974657
+
+func formatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func newUUIDv4() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return formatUUID(b), nil
+}
+
+func newUUIDv7(now time.Time) (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	ms := uint64(now.UnixMilli())
+	b[0], b[1], b[2], b[3], b[4], b[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+
+	return formatUUID(b), nil
+}
+
+func newULID(now time.Time) (string, error) {
+	entropy, err := randomBytes(10)
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+	ms := uint64(now.UnixMilli())
+	b[0], b[1], b[2], b[3], b[4], b[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], entropy)
+
+	// 128 bits as 26 Crockford base32 characters, the first one holds only 3 bits
+	num := new(big.Int).SetBytes(b)
+	out := make([]byte, 26)
+	mask := big.NewInt(31)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[new(big.Int).And(num, mask).Int64()]
+		num.Rsh(num, 5)
+	}
+
+	return string(out), nil
+}
+
+func newNanoID(alphabet string, size int) (string, error) {
+	if alphabet == "" {
+		alphabet = nanoIDAlphabet
+	}
+	// indexed by rune, so a multibyte alphabet still gives valid UTF-8
+	runes := []rune(alphabet)
+	if len(runes) > 255 {
+		return "", errors.New("NanoID alphabet can have at most 255 characters")
+	}
+
+	out := make([]rune, max(size, 0))
+	for i := range out {
+		n, err := randomInt(len(runes))
+		if err != nil {
+			return "", err
+		}
+		out[i] = runes[n]
+	}
+
+	return string(out), nil
+}
+
+func encodeBase62(b []byte, width int) string {
+	num := new(big.Int).SetBytes(b)
+	radix := big.NewInt(62)
+	mod := new(big.Int)
+
+	out := make([]byte, 0, width)
+	for num.Sign() > 0 {
+		num.DivMod(num, radix, mod)
+		out = append(out, base62Alphabet[mod.Int64()])
+	}
+	for len(out) < width {
+		out = append(out, '0')
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+func newKSUID(now time.Time) (string, error) {
+	payload, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint32(b, uint32(now.Unix()-ksuidEpoch))
+	copy(b[4:], payload)
+
+	return encodeBase62(b, 27), nil
+}
+
+// SnowflakeGenerator hands out 41-bit millisecond, 10-bit machine, 12-bit sequence IDs.
+type SnowflakeGenerator struct {
+	mu       sync.Mutex
+	Epoch    int64
+	Machine  int64
+	lastMS   int64
+	sequence int64
+}
+
+func (g *SnowflakeGenerator) Next(now time.Time) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Machine < 0 || g.Machine > 1023 {
+		return 0, fmt.Errorf("machine ID must be between 0 and 1023, got %d", g.Machine)
+	}
+
+	ms := now.UnixMilli() - g.Epoch
+	if ms < 0 {
+		return 0, errors.New("snowflake epoch is in the future")
+	}
+
+	if ms <= g.lastMS {
+		// same millisecond, or the clock went back: keep counting from the last one
+		ms = g.lastMS
+		g.sequence = (g.sequence + 1) & 0xfff
+		if g.sequence == 0 {
+			ms++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMS = ms
+
+	return ms<<22 | g.Machine<<12 | g.sequence, nil
+}
+
+func generateID(kind string, now time.Time, nanoAlphabet string, nanoSize int, snowflake *SnowflakeGenerator) (string, error) {
+	switch kind {
+	case "UUID v4":
+		return newUUIDv4()
+	case "UUID v7":
+		return newUUIDv7(now)
+	case "ULID":
+		return newULID(now)
+	case "NanoID":
+		return newNanoID(nanoAlphabet, nanoSize)
+	case "KSUID":
+		return newKSUID(now)
+	case "Snowflake":
+		id, err := snowflake.Next(now)
+		return strconv.FormatInt(id, 10), err
+	}
+
+	return "", fmt.Errorf("unknown ID type %q", kind)
+}
+
+func describeTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s, unix %d)", t.UTC().Format("2006-01-02 15:04:05.000 MST"), t.Local().Format("2006-01-02 15:04:05 -0700"), t.Unix())
+}
+
+// DecodeID recognises time-ordered IDs and extracts their embedded timestamp.
+func DecodeID(id string, snowflakeEpoch int64) ([]string, error) {
+	id = strings.TrimSpace(id)
+
+	if compact := strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(id, "{"), "}"), "-", ""); len(compact) == 32 {
+		b, err := hex.DecodeString(compact)
+		if err == nil {
+			return decodeUUID(b), nil
+		}
+	}
+
+	switch {
+	case len(id) == 26:
+		return decodeULID(id)
+	case len(id) == 27:
+		return decodeKSUID(id)
+	}
+
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil && n > 0 {
+		ms := n>>22 + snowflakeEpoch
+		return []string{
+			"Type: Snowflake",
+			"Time: " + describeTime(time.UnixMilli(ms)),
+			fmt.Sprintf("Machine: %d", n>>12&0x3ff),
+			fmt.Sprintf("Sequence: %d", n&0xfff),
+		}, nil
+	}
+
+	return nil, errors.New("not a recognised UUID, ULID, KSUID or Snowflake ID")
+}
+
+func decodeUUID(b []byte) []string {
+	version := b[6] >> 4
+	lines := []string{fmt.Sprintf("Type: UUID version %d", version)}
+
+	switch version {
+	case 1, 6:
+		// 60-bit count of 100ns intervals since 1582-10-15
+		var ticks uint64
+		if version == 1 {
+			ticks = uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)<<48 | uint64(binary.BigEndian.Uint16(b[4:6]))<<32 | uint64(binary.BigEndian.Uint32(b[0:4]))
+		} else {
+			ticks = uint64(binary.BigEndian.Uint32(b[0:4]))<<28 | uint64(binary.BigEndian.Uint16(b[4:6]))<<12 | uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)
+		}
+		const gregorianToUnixMS = 12219292800000
+		t := time.UnixMilli(int64(ticks/1e4) - gregorianToUnixMS)
+		lines = append(lines, "Time: "+describeTime(t), "Node: "+hex.EncodeToString(b[10:]))
+	case 7:
+		ms := int64(b[0])<<40 | int64(b[1])<<32 | int64(b[2])<<24 | int64(b[3])<<16 | int64(b[4])<<8 | int64(b[5])
+		lines = append(lines, "Time: "+describeTime(time.UnixMilli(ms)))
+	default:
+		lines = append(lines, "This version has no embedded timestamp")
+	}
+
+	return lines
+}
+
+func decodeULID(id string) ([]string, error) {
+	num := new(big.Int)
+	for i, c := range strings.ToUpper(id) {
+		// Crockford allows I/L for 1 and O for 0
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+
+		idx := strings.IndexRune(crockfordAlphabet, c)
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid ULID character %q at offset %d", c, i)
+		}
+		num.Lsh(num, 5)
+		num.Or(num, big.NewInt(int64(idx)))
+	}
+
+	if num.BitLen() > 128 {
+		return nil, errors.New("ULID overflows 128 bits")
+	}
+
+	b := num.FillBytes(make([]byte, 16))
+	ms := int64(b[0])<<40 | int64(b[1])<<32 | int64(b[2])<<24 | int64(b[3])<<16 | int64(b[4])<<8 | int64(b[5])
+
+	return []string{
+		"Type: ULID",
+		"Time: " + describeTime(time.UnixMilli(ms)),
+		"Randomness: " + hex.EncodeToString(b[6:]),
+	}, nil
+}
+
+func decodeKSUID(id string) ([]string, error) {
+	num := new(big.Int)
+	radix := big.NewInt(62)
+	for i := 0; i < len(id); i++ {
+		idx := strings.IndexByte(base62Alphabet, id[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid KSUID character %q at offset %d", id[i], i)
+		}
+		num.Mul(num, radix)
+		num.Add(num, big.NewInt(int64(idx)))
+	}
+
+	if num.BitLen() > 160 {
+		return nil, errors.New("KSUID overflows 160 bits")
+	}
+
+	b := num.FillBytes(make([]byte, 20))
+	seconds := int64(binary.BigEndian.Uint32(b[:4])) + ksuidEpoch
+
+	return []string{
+		"Type: KSUID",
+		"Time: " + describeTime(time.Unix(seconds, 0)),
+		"Payload: " + hex.EncodeToString(b[4:]),
+	}, nil
+}
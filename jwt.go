@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type JWT struct {
+	Raw       string
+	Header    map[string]interface{}
+	Payload   map[string]interface{}
+	Signature []byte
+
+	HeaderJSON  []byte
+	PayloadJSON []byte
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+var decodeBase64URL = decodeBase64Loose(base64.RawURLEncoding)
+
+func parseJWT(token string) (*JWT, error) {
+	token = strings.TrimPrefix(strings.TrimSpace(token), "Bearer ")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token must have 3 parts separated by '.', got %d", len(parts))
+	}
+
+	jwt := &JWT{Raw: token}
+	names := []string{"header", "payload", "signature"}
+	decoded := make([][]byte, 3)
+
+	for i, part := range parts {
+		data, err := decodeBase64URL([]byte(part))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", names[i], err)
+		}
+		decoded[i] = data
+	}
+
+	if err := json.Unmarshal(decoded[0], &jwt.Header); err != nil {
+		return nil, fmt.Errorf("header is not JSON: %w", err)
+	}
+	if err := json.Unmarshal(decoded[1], &jwt.Payload); err != nil {
+		return nil, fmt.Errorf("payload is not JSON: %w", err)
+	}
+
+	jwt.HeaderJSON = prettyJSON(decoded[0])
+	jwt.PayloadJSON = prettyJSON(decoded[1])
+	jwt.Signature = decoded[2]
+
+	return jwt, nil
+}
+
+func prettyJSON(data []byte) []byte {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "    "); err != nil {
+		return data
+	}
+
+	return out.Bytes()
+}
+
+func (t *JWT) Alg() string {
+	alg, _ := t.Header["alg"].(string)
+
+	return alg
+}
+
+func (t *JWT) Kid() string {
+	kid, _ := t.Header["kid"].(string)
+
+	return kid
+}
+
+func (t *JWT) signingInput() []byte {
+	return []byte(t.Raw[:strings.LastIndex(t.Raw, ".")])
+}
+
+func (t *JWT) claimTime(name string) (time.Time, bool) {
+	v, ok := t.Payload[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(v), 0), true
+}
+
+// TimeClaims describes exp, iat and nbf in human readable form.
+func (t *JWT) TimeClaims(now time.Time) []string {
+	var lines []string
+
+	if iat, ok := t.claimTime("iat"); ok {
+		lines = append(lines, fmt.Sprintf("Issued at (iat): %s, %s ago", iat.Local().Format(time.RFC1123), now.Sub(iat).Round(time.Second)))
+	}
+
+	if nbf, ok := t.claimTime("nbf"); ok {
+		status := "active"
+		if now.Before(nbf) {
+			status = fmt.Sprintf("NOT YET VALID, starts in %s", nbf.Sub(now).Round(time.Second))
+		}
+		lines = append(lines, fmt.Sprintf("Not before (nbf): %s, %s", nbf.Local().Format(time.RFC1123), status))
+	}
+
+	if exp, ok := t.claimTime("exp"); ok {
+		status := fmt.Sprintf("valid for %s", exp.Sub(now).Round(time.Second))
+		if !now.Before(exp) {
+			status = fmt.Sprintf("EXPIRED %s ago", now.Sub(exp).Round(time.Second))
+		}
+		lines = append(lines, fmt.Sprintf("Expires (exp): %s, %s", exp.Local().Format(time.RFC1123), status))
+	} else {
+		lines = append(lines, "Expires (exp): no expiry claim")
+	}
+
+	return lines
+}
+
+func jwtHash(alg string) (crypto.Hash, error) {
+	switch alg[2:] {
+	case "256":
+		return crypto.SHA256, nil
+	case "384":
+		return crypto.SHA384, nil
+	case "512":
+		return crypto.SHA512, nil
+	}
+
+	return 0, fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func hmacHash(h crypto.Hash) func() hash.Hash {
+	switch h {
+	case crypto.SHA384:
+		return sha512.New384
+	case crypto.SHA512:
+		return sha512.New
+	}
+
+	return sha256.New
+}
+
+// VerifyJWT checks the signature with an HMAC secret for HS* or a PEM/JWK public key
+// for RS*, PS*, ES* and EdDSA.
+func VerifyJWT(t *JWT, key string, secretIsBase64 bool) error {
+	alg := t.Alg()
+	if alg == "" || strings.EqualFold(alg, "none") {
+		return errors.New("token is not signed (alg none)")
+	}
+
+	if strings.TrimSpace(key) == "" {
+		return errors.New("secret or public key must be fill")
+	}
+
+	input := t.signingInput()
+
+	if alg == "EdDSA" {
+		pub, err := parsePublicKey(key, t.Kid())
+		if err != nil {
+			return err
+		}
+
+		edKey, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("EdDSA needs an Ed25519 key, got %T", pub)
+		}
+		if !ed25519.Verify(edKey, input, t.Signature) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	h, err := jwtHash(alg)
+	if err != nil {
+		return err
+	}
+
+	if alg[:2] == "HS" {
+		secret := []byte(key)
+		if secretIsBase64 {
+			secret, err = decodeBase64Loose(base64.RawStdEncoding)([]byte(strings.NewReplacer("-", "+", "_", "/").Replace(key)))
+			if err != nil {
+				return fmt.Errorf("secret: %w", err)
+			}
+		} else if strings.HasPrefix(strings.TrimSpace(key), "{") {
+			// JSON is a JWK, never a secret to use as written
+			jwk, err := parseJWK(key, t.Kid())
+			if err != nil {
+				return err
+			}
+			if jwk.Kty != "oct" {
+				return fmt.Errorf("%s needs an oct JWK, got kty %q", alg, jwk.Kty)
+			}
+			if secret, err = decodeBase64URL([]byte(jwk.K)); err != nil {
+				return fmt.Errorf("jwk k: %w", err)
+			}
+		}
+
+		mac := hmac.New(hmacHash(h), secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), t.Signature) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+
+	hasher := h.New()
+	hasher.Write(input)
+	digest := hasher.Sum(nil)
+
+	pub, err := parsePublicKey(key, t.Kid())
+	if err != nil {
+		return err
+	}
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an RSA key, got %T", alg, pub)
+		}
+
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(rsaKey, h, digest, t.Signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, h, digest, t.Signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+		}
+		if err != nil {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	case "ES":
+		ecKey, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%s needs an ECDSA key, got %T", alg, pub)
+		}
+
+		// JWS uses the fixed size r || s form, not ASN.1
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(t.Signature) != 2*size {
+			return fmt.Errorf("ECDSA signature must be %d bytes, got %d", 2*size, len(t.Signature))
+		}
+
+		r := new(big.Int).SetBytes(t.Signature[:size])
+		s := new(big.Int).SetBytes(t.Signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func parsePublicKey(key string, kid string) (crypto.PublicKey, error) {
+	key = strings.TrimSpace(key)
+
+	if strings.HasPrefix(key, "{") {
+		jwk, err := parseJWK(key, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return jwk.PublicKey()
+	}
+
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("key is neither PEM nor JWK")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return cert.PublicKey, nil
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q, paste a public key or certificate", block.Type)
+}
+
+// parseJWK accepts a single JWK or a JWK set, picking the key matching kid.
+func parseJWK(key string, kid string) (*JWK, error) {
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(key), &set); err == nil && len(set.Keys) > 0 {
+		for i := range set.Keys {
+			if kid == "" || set.Keys[i].Kid == kid {
+				return &set.Keys[i], nil
+			}
+		}
+
+		return nil, fmt.Errorf("no key with kid %q in JWK set", kid)
+	}
+
+	var jwk JWK
+	if err := json.Unmarshal([]byte(key), &jwk); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+	if jwk.Kty == "" {
+		return nil, errors.New("invalid JWK: kty is missing")
+	}
+
+	return &jwk, nil
+}
+
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	field := func(name, value string) (*big.Int, error) {
+		data, err := decodeBase64URL([]byte(value))
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: %w", name, err)
+		}
+
+		return new(big.Int).SetBytes(data), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := field("n", k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := field("e", k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %q", k.Crv)
+		}
+
+		x, err := field("x", k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := field("y", k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", k.Crv)
+		}
+
+		x, err := decodeBase64URL([]byte(k.X))
+		if err != nil {
+			return nil, fmt.Errorf("jwk x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Ed25519 key must be %d bytes, got %d", ed25519.PublicKeySize, len(x))
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported JWK key type %q", k.Kty)
+}
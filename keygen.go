@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var listSSHKeyType = []string{"Ed25519", "ECDSA P-256", "ECDSA P-384", "ECDSA P-521", "RSA 2048", "RSA 3072", "RSA 4096"}
+
+var listTLSKeyType = []string{"ECDSA P-256", "ECDSA P-384", "Ed25519", "RSA 2048", "RSA 4096"}
+
+var listCertKind = []string{"Self-signed", "CA + signed certificate"}
+
+// KeyFile is one file of a generated bundle, private keys are written 0600.
+type KeyFile struct {
+	Name    string
+	Data    []byte
+	Private bool
+}
+
+// newKeyPair generates a private key of kind, as listed in listSSHKeyType.
+func newKeyPair(kind string) (crypto.Signer, error) {
+	switch kind {
+	case "Ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	case "ECDSA P-256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ECDSA P-384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ECDSA P-521":
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "RSA 2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "RSA 3072":
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case "RSA 4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	}
+
+	return nil, fmt.Errorf("unknown key type %q", kind)
+}
+
+// sshFileName follows ssh-keygen's default names: id_ed25519, id_ecdsa, id_rsa.
+func sshFileName(kind string) string {
+	name, _, _ := strings.Cut(strings.ToLower(kind), " ")
+
+	return "id_" + name
+}
+
+// GenerateSSHKey returns the OpenSSH private key, the authorized_keys line and
+// its SHA256 fingerprint. An empty passphrase leaves the private key unencrypted.
+func GenerateSSHKey(kind, comment, passphrase string) ([]KeyFile, string, error) {
+	key, err := newKeyPair(kind)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, []byte(passphrase))
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	public, err := ssh.NewPublicKey(key.Public())
+	if err != nil {
+		return nil, "", err
+	}
+
+	authorized := bytes.TrimSpace(ssh.MarshalAuthorizedKey(public))
+	if comment != "" {
+		authorized = append(authorized, ' ')
+		authorized = append(authorized, comment...)
+	}
+	authorized = append(authorized, '\n')
+
+	name := sshFileName(kind)
+	files := []KeyFile{
+		{Name: name, Data: pem.EncodeToMemory(block), Private: true},
+		{Name: name + ".pub", Data: authorized},
+	}
+
+	return files, ssh.FingerprintSHA256(public), nil
+}
+
+type CertOptions struct {
+	CommonName   string
+	Organization string
+	SANs         []string
+	ValidDays    int
+	KeyType      string
+	Kind         string
+}
+
+// parseSANs sorts a comma or newline separated list into IPs, emails and DNS names.
+func parseSANs(text string) (dns []string, ips []net.IP, emails []string, err error) {
+	for _, san := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' || r == ' ' }) {
+		switch {
+		case net.ParseIP(san) != nil:
+			ips = append(ips, net.ParseIP(san))
+		case strings.Contains(san, "@"):
+			if _, err := mail.ParseAddress(san); err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid email SAN %q", san)
+			}
+			emails = append(emails, san)
+		default:
+			name := strings.TrimPrefix(san, "*.")
+			if name == "" || strings.ContainsAny(name, "*/:") {
+				return nil, nil, nil, fmt.Errorf("invalid DNS SAN %q", san)
+			}
+			dns = append(dns, san)
+		}
+	}
+
+	return dns, ips, emails, nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// GenerateCertificate creates a self-signed certificate, or a CA plus a
+// certificate it signed. The returned summary describes what was made.
+func GenerateCertificate(opts CertOptions) ([]KeyFile, string, error) {
+	if opts.CommonName == "" {
+		return nil, "", errors.New("common name must be fill")
+	}
+	if opts.ValidDays <= 0 {
+		return nil, "", fmt.Errorf("validity must be at least 1 day, got %d", opts.ValidDays)
+	}
+
+	dns, ips, emails, err := parseSANs(strings.Join(opts.SANs, ","))
+	if err != nil {
+		return nil, "", err
+	}
+
+	subject := pkix.Name{CommonName: opts.CommonName}
+	if opts.Organization != "" {
+		subject.Organization = []string{opts.Organization}
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(time.Duration(opts.ValidDays) * 24 * time.Hour)
+
+	key, err := newKeyPair(opts.KeyType)
+	if err != nil {
+		return nil, "", err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, "", err
+	}
+
+	leaf := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               subject,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              dns,
+		IPAddresses:           ips,
+		EmailAddresses:        emails,
+	}
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		leaf.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	parent, signer := leaf, key
+	var files []KeyFile
+
+	if opts.Kind == "CA + signed certificate" {
+		caKey, err := newKeyPair(opts.KeyType)
+		if err != nil {
+			return nil, "", err
+		}
+		caSerial, err := serialNumber()
+		if err != nil {
+			return nil, "", err
+		}
+
+		caSubject := subject
+		caSubject.CommonName = opts.CommonName + " Local CA"
+
+		ca := &x509.Certificate{
+			SerialNumber:          caSerial,
+			Subject:               caSubject,
+			NotBefore:             notBefore,
+			NotAfter:              notAfter,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+			MaxPathLenZero:        true,
+		}
+
+		caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+		if err != nil {
+			return nil, "", err
+		}
+		if ca, err = x509.ParseCertificate(caDER); err != nil {
+			return nil, "", err
+		}
+
+		caKeyPEM, err := encodePrivateKeyPEM(caKey)
+		if err != nil {
+			return nil, "", err
+		}
+
+		files = append(files,
+			KeyFile{Name: "ca.pem", Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})},
+			KeyFile{Name: "ca-key.pem", Data: caKeyPEM, Private: true},
+		)
+		parent, signer = ca, caKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, leaf, parent, key.Public(), signer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, "", err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	files = append([]KeyFile{
+		{Name: "cert.pem", Data: certPEM},
+		{Name: "key.pem", Data: keyPEM, Private: true},
+	}, files...)
+
+	if len(files) > 2 {
+		// leaf first, then the CA, as servers expect a chain file
+		files = append(files, KeyFile{Name: "fullchain.pem", Data: append(append([]byte{}, certPEM...), files[2].Data...)})
+	}
+
+	fingerprint := sha256.Sum256(der)
+	summary := []string{
+		"Subject: " + subject.String(),
+		fmt.Sprintf("Valid: %s → %s", notBefore.Format("2006-01-02"), notAfter.Format("2006-01-02")),
+		"SHA-256: " + strings.ToUpper(hex.EncodeToString(fingerprint[:])),
+	}
+	if len(dns)+len(ips)+len(emails) > 0 {
+		var sans []string
+		sans = append(sans, dns...)
+		for _, ip := range ips {
+			sans = append(sans, ip.String())
+		}
+		sans = append(sans, emails...)
+		summary = append(summary, "SANs: "+strings.Join(sans, ", "))
+	}
+	if parent != leaf {
+		summary = append(summary, "Issuer: "+parent.Subject.String())
+	}
+
+	return files, strings.Join(summary, "\n"), nil
+}
+
+// writeKeyFiles writes every file into dir, refusing to overwrite existing ones.
+func writeKeyFiles(dir string, files []KeyFile) error {
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(dir, file.Name)); err == nil {
+			return fmt.Errorf("%s already exists in %s", file.Name, dir)
+		}
+	}
+
+	for _, file := range files {
+		perm := os.FileMode(0o644)
+		if file.Private {
+			perm = 0o600
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Data, perm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
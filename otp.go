@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var listOTPType = []string{"TOTP", "HOTP"}
+
+var listOTPAlgorithm = []string{"SHA1", "SHA256", "SHA512"}
+
+var otpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// OTPAccount is one authenticator entry, following the otpauth:// key URI format.
+type OTPAccount struct {
+	Type      string `json:"type"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Secret    string `json:"secret"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period,omitempty"`
+	Counter   uint64 `json:"counter,omitempty"`
+}
+
+// newOTPSecret returns size random bytes as unpadded base32, 20 bytes matches SHA1.
+func newOTPSecret(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return otpBase32.EncodeToString(b), nil
+}
+
+// decodeOTPSecret accepts the lowercase, spaced and padded forms authenticator apps show.
+func decodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.TrimRight(strings.ToUpper(stripSpaces([]byte(secret))), "=")
+	if secret == "" {
+		return nil, errors.New("secret must be fill")
+	}
+
+	key, err := otpBase32.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("secret is not valid base32: %w", err)
+	}
+
+	return key, nil
+}
+
+func (a OTPAccount) Validate() error {
+	if a.Type != "TOTP" && a.Type != "HOTP" {
+		return fmt.Errorf("unknown OTP type %q", a.Type)
+	}
+	if a.Digits != 6 && a.Digits != 8 {
+		return fmt.Errorf("digits must be 6 or 8, got %d", a.Digits)
+	}
+	if a.Type == "TOTP" && a.Period <= 0 {
+		return fmt.Errorf("period must be positive, got %d", a.Period)
+	}
+	if _, err := hashConstructor(otpHashName(a.Algorithm)); err != nil {
+		return err
+	}
+
+	_, err := decodeOTPSecret(a.Secret)
+	return err
+}
+
+// otpHashName maps the otpauth algorithm names onto listHashAlgorithm.
+func otpHashName(algorithm string) string {
+	switch algorithm {
+	case "SHA256":
+		return "SHA-256"
+	case "SHA512":
+		return "SHA-512"
+	}
+
+	return "SHA-1"
+}
+
+// HOTP implements RFC 4226 with the hash choices of RFC 6238.
+func HOTP(key []byte, counter uint64, digits int, algorithm string) (string, error) {
+	newHash, err := hashConstructor(otpHashName(algorithm))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod), nil
+}
+
+// Code returns the current code, and for TOTP how long it stays valid.
+func (a OTPAccount) Code(now time.Time) (string, time.Duration, error) {
+	key, err := decodeOTPSecret(a.Secret)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if a.Type == "HOTP" {
+		code, err := HOTP(key, a.Counter, a.Digits, a.Algorithm)
+		return code, 0, err
+	}
+
+	period := int64(a.Period)
+	step := now.Unix() / period
+	remaining := time.Duration(period-now.Unix()%period) * time.Second
+
+	code, err := HOTP(key, uint64(step), a.Digits, a.Algorithm)
+	return code, remaining, err
+}
+
+func (a OTPAccount) Label() string {
+	if a.Issuer == "" {
+		return a.Account
+	}
+
+	return a.Issuer + ":" + a.Account
+}
+
+// URI builds the otpauth:// link authenticator apps scan.
+func (a OTPAccount) URI() string {
+	query := url.Values{}
+	query.Set("secret", strings.ToUpper(stripSpaces([]byte(a.Secret))))
+	if a.Issuer != "" {
+		query.Set("issuer", a.Issuer)
+	}
+	query.Set("algorithm", a.Algorithm)
+	query.Set("digits", strconv.Itoa(a.Digits))
+	if a.Type == "HOTP" {
+		query.Set("counter", strconv.FormatUint(a.Counter, 10))
+	} else {
+		query.Set("period", strconv.Itoa(a.Period))
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     strings.ToLower(a.Type),
+		Path:     "/" + a.Label(),
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+func parseOTPAuthURI(raw string) (OTPAccount, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return OTPAccount{}, err
+	}
+	if u.Scheme != "otpauth" {
+		return OTPAccount{}, fmt.Errorf("expected an otpauth:// URI, got %q", u.Scheme+"://")
+	}
+
+	query := u.Query()
+	a := OTPAccount{
+		Type:      strings.ToUpper(u.Host),
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Algorithm: strings.ToUpper(query.Get("algorithm")),
+		Digits:    6,
+		Period:    30,
+	}
+	if a.Algorithm == "" {
+		a.Algorithm = "SHA1"
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		if a.Issuer == "" {
+			a.Issuer = issuer
+		}
+		label = account
+	}
+	a.Account = strings.TrimSpace(label)
+
+	if digits := query.Get("digits"); digits != "" {
+		if a.Digits, err = strconv.Atoi(digits); err != nil {
+			return OTPAccount{}, fmt.Errorf("invalid digits %q", digits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		if a.Period, err = strconv.Atoi(period); err != nil {
+			return OTPAccount{}, fmt.Errorf("invalid period %q", period)
+		}
+	}
+	if counter := query.Get("counter"); counter != "" {
+		if a.Counter, err = strconv.ParseUint(counter, 10, 64); err != nil {
+			return OTPAccount{}, fmt.Errorf("invalid counter %q", counter)
+		}
+	}
+
+	return a, a.Validate()
+}
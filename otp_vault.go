@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// otpVaultFile is the on-disk form: the account list as JSON, sealed with
+// AES-256-GCM under a key derived from the master password with argon2id.
+type otpVaultFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+var errOTPVaultPassword = errors.New("wrong master password, or the vault file is damaged")
+
+func otpVaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sesterdamp", "otp_vault.json"), nil
+}
+
+func otpVaultKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, 3, 64*1024, 4, 32)
+}
+
+// loadOTPVault decrypts the stored accounts, a missing vault is an empty one.
+func loadOTPVault(password string) ([]OTPAccount, error) {
+	path, err := otpVaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vault otpVaultFile
+	if err := json.Unmarshal(data, &vault); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(otpVaultKey(password, vault.Salt))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(vault.Nonce) != gcm.NonceSize() {
+		return nil, errOTPVaultPassword
+	}
+
+	plain, err := gcm.Open(nil, vault.Nonce, vault.Data, nil)
+	if err != nil {
+		return nil, errOTPVaultPassword
+	}
+
+	var accounts []OTPAccount
+	if err := json.Unmarshal(plain, &accounts); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// saveOTPVault re-encrypts everything with a fresh salt and nonce.
+func saveOTPVault(password string, accounts []OTPAccount) error {
+	path, err := otpVaultPath()
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(accounts)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(otpVaultKey(password, salt))
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(otpVaultFile{
+		Version: 1,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+)
+
+//go:embed assets/eff_large_wordlist.txt
+var effLargeWordlistText string
+
+var effLargeWordlist = parseWordlist(effLargeWordlistText)
+
+var listCapitalization = []string{"lowercase", "Capitalize", "UPPERCASE", "Random"}
+
+const passphraseSymbols = "!@#$%&*-_+=?"
+
+type PassphraseOptions struct {
+	Words          int
+	Separator      string
+	Capitalization string
+	InjectDigit    bool
+	InjectSymbol   bool
+	Wordlist       []string
+}
+
+// parseWordlist accepts plain one-word-per-line lists as well as the EFF
+// "11111<tab>word" dice format, skipping blanks, comments and duplicates.
+func parseWordlist(text string) []string {
+	seen := map[string]bool{}
+	var words []string
+
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		word := fields[len(fields)-1]
+		if seen[word] {
+			continue
+		}
+
+		seen[word] = true
+		words = append(words, word)
+	}
+
+	return words
+}
+
+func capitalizeWord(word string) string {
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func generatePassphrase(opts PassphraseOptions) (string, error) {
+	if len(opts.Wordlist) < 2 {
+		return "", errors.New("wordlist must have at least 2 words")
+	}
+	if opts.Words <= 0 {
+		return "", errors.New("word count must be greater than 0")
+	}
+
+	words := make([]string, opts.Words)
+	for i := range words {
+		n, err := randomInt(len(opts.Wordlist))
+		if err != nil {
+			return "", err
+		}
+
+		word := opts.Wordlist[n]
+		switch opts.Capitalization {
+		case "Capitalize":
+			word = capitalizeWord(word)
+		case "UPPERCASE":
+			word = strings.ToUpper(word)
+		case "Random":
+			upper, err := randomInt(2)
+			if err != nil {
+				return "", err
+			}
+			if upper == 1 {
+				word = capitalizeWord(word)
+			}
+		}
+		words[i] = word
+	}
+
+	inject := func(charset string) error {
+		pos, err := randomInt(len(words))
+		if err != nil {
+			return err
+		}
+		n, err := randomInt(len(charset))
+		if err != nil {
+			return err
+		}
+
+		words[pos] += string(charset[n])
+		return nil
+	}
+
+	if opts.InjectDigit {
+		if err := inject("0123456789"); err != nil {
+			return "", err
+		}
+	}
+	if opts.InjectSymbol {
+		if err := inject(passphraseSymbols); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(words, opts.Separator), nil
+}
+
+// passphraseEntropy is the entropy in bits assuming the attacker knows the
+// wordlist and every option, only the random choices count.
+func passphraseEntropy(opts PassphraseOptions) float64 {
+	if len(opts.Wordlist) == 0 || opts.Words <= 0 {
+		return 0
+	}
+
+	bits := float64(opts.Words) * math.Log2(float64(len(opts.Wordlist)))
+	if opts.Capitalization == "Random" {
+		bits += float64(opts.Words)
+	}
+	if opts.InjectDigit {
+		bits += math.Log2(float64(10 * opts.Words))
+	}
+	if opts.InjectSymbol {
+		bits += math.Log2(float64(len(passphraseSymbols) * opts.Words))
+	}
+
+	return bits
+}
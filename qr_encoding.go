@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yeqown/go-qrcode/v2"
+)
+
+var listQRErrorCorrection = []string{"Low (7%)", "Medium (15%)", "Quartile (25%)", "High (30%)"}
+
+var listQREncodingMode = []string{"Auto", "Numeric", "Alphanumeric", "Byte"}
+
+// listQRVersion is the smallest version to use, "Auto" takes whatever fits.
+var listQRVersion = func() []string {
+	versions := []string{"Auto"}
+	for v := 1; v <= 40; v++ {
+		versions = append(versions, fmt.Sprint(v))
+	}
+
+	return versions
+}()
+
+// qrCapacity is how many numeric, alphanumeric and byte characters fit in
+// each version at L, M, Q and H, the same table go-qrcode picks versions from.
+var qrCapacity = [40][4][3]int{
+	{{41, 25, 17}, {34, 20, 14}, {27, 16, 11}, {17, 10, 7}},                          // 1
+	{{77, 47, 32}, {63, 38, 26}, {48, 29, 20}, {34, 20, 14}},                         // 2
+	{{127, 77, 53}, {101, 61, 42}, {77, 47, 32}, {58, 35, 24}},                       // 3
+	{{187, 114, 78}, {149, 90, 62}, {111, 67, 46}, {82, 50, 34}},                     // 4
+	{{255, 154, 106}, {202, 122, 84}, {144, 87, 60}, {106, 64, 44}},                  // 5
+	{{322, 195, 134}, {255, 154, 106}, {178, 108, 74}, {139, 84, 58}},                // 6
+	{{370, 224, 154}, {293, 178, 122}, {207, 125, 86}, {154, 93, 64}},                // 7
+	{{461, 279, 192}, {365, 221, 152}, {259, 157, 108}, {202, 122, 84}},              // 8
+	{{552, 335, 230}, {432, 262, 180}, {312, 189, 130}, {235, 143, 98}},              // 9
+	{{652, 395, 271}, {513, 311, 213}, {364, 221, 151}, {288, 174, 119}},             // 10
+	{{772, 468, 321}, {604, 366, 251}, {427, 259, 177}, {331, 200, 137}},             // 11
+	{{883, 535, 367}, {691, 419, 287}, {489, 296, 203}, {374, 227, 155}},             // 12
+	{{1022, 619, 425}, {796, 483, 331}, {580, 352, 241}, {427, 259, 177}},            // 13
+	{{1101, 667, 458}, {871, 528, 362}, {621, 376, 258}, {468, 283, 194}},            // 14
+	{{1250, 758, 520}, {991, 600, 412}, {703, 426, 292}, {530, 321, 220}},            // 15
+	{{1408, 854, 586}, {1082, 656, 450}, {775, 470, 322}, {602, 365, 250}},           // 16
+	{{1548, 938, 644}, {1212, 734, 504}, {876, 531, 364}, {674, 408, 280}},           // 17
+	{{1725, 1046, 718}, {1346, 816, 560}, {948, 574, 394}, {746, 452, 310}},          // 18
+	{{1903, 1153, 792}, {1500, 909, 624}, {1063, 644, 442}, {813, 493, 338}},         // 19
+	{{2061, 1249, 858}, {1600, 970, 666}, {1159, 702, 482}, {919, 557, 382}},         // 20
+	{{2232, 1352, 929}, {1708, 1035, 711}, {1224, 742, 509}, {969, 587, 403}},        // 21
+	{{2409, 1460, 1003}, {1872, 1134, 779}, {1358, 823, 565}, {1056, 640, 439}},      // 22
+	{{2620, 1588, 1091}, {2059, 1248, 857}, {1468, 890, 611}, {1108, 672, 461}},      // 23
+	{{2812, 1704, 1171}, {2188, 1326, 911}, {1588, 963, 661}, {1228, 744, 511}},      // 24
+	{{3057, 1853, 1273}, {2395, 1451, 997}, {1718, 1041, 715}, {1286, 779, 535}},     // 25
+	{{3283, 1990, 1367}, {2544, 1542, 1059}, {1804, 1094, 751}, {1425, 864, 593}},    // 26
+	{{3517, 2132, 1465}, {2701, 1637, 1125}, {1933, 1172, 805}, {1501, 910, 625}},    // 27
+	{{3669, 2223, 1528}, {2857, 1732, 1190}, {2085, 1263, 868}, {1581, 958, 658}},    // 28
+	{{3909, 2369, 1628}, {3035, 1839, 1264}, {2181, 1322, 908}, {1677, 1016, 698}},   // 29
+	{{4158, 2520, 1732}, {3289, 1994, 1370}, {2358, 1429, 982}, {1782, 1080, 742}},   // 30
+	{{4417, 2677, 1840}, {3486, 2113, 1452}, {2473, 1499, 1030}, {1897, 1150, 790}},  // 31
+	{{4686, 2840, 1952}, {3693, 2238, 1538}, {2670, 1618, 1112}, {2022, 1226, 842}},  // 32
+	{{4965, 3009, 2068}, {3909, 2369, 1628}, {2805, 1700, 1168}, {2157, 1307, 898}},  // 33
+	{{5253, 3183, 2188}, {4134, 2506, 1722}, {2949, 1787, 1228}, {2301, 1394, 958}},  // 34
+	{{5529, 3351, 2303}, {4343, 2632, 1809}, {3081, 1867, 1283}, {2361, 1431, 983}},  // 35
+	{{5836, 3537, 2431}, {4588, 2780, 1911}, {3244, 1966, 1351}, {2524, 1530, 1051}}, // 36
+	{{6153, 3729, 2563}, {4775, 2894, 1989}, {3417, 2071, 1423}, {2625, 1591, 1093}}, // 37
+	{{6479, 3927, 2699}, {5039, 3054, 2099}, {3599, 2181, 1499}, {2735, 1658, 1139}}, // 38
+	{{6743, 4087, 2809}, {5313, 3220, 2213}, {3791, 2298, 1579}, {2927, 1774, 1219}}, // 39
+	{{7089, 4296, 2953}, {5596, 3391, 2331}, {3993, 2420, 1663}, {3057, 1852, 1273}}, // 40
+}
+
+// qrErrorCorrection maps a listQRErrorCorrection name to its index in qrCapacity.
+func qrErrorCorrection(name string) int {
+	if i := slices.Index(listQRErrorCorrection, name); i >= 0 {
+		return i
+	}
+
+	return 2
+}
+
+func qrErrorCorrectionOption(name string) qrcode.EncodeOption {
+	switch qrErrorCorrection(name) {
+	case 0:
+		return qrcode.WithErrorCorrectionLevel(qrcode.ErrorCorrectionLow)
+	case 1:
+		return qrcode.WithErrorCorrectionLevel(qrcode.ErrorCorrectionMedium)
+	case 3:
+		return qrcode.WithErrorCorrectionLevel(qrcode.ErrorCorrectionHighest)
+	}
+
+	return qrcode.WithErrorCorrectionLevel(qrcode.ErrorCorrectionQuart)
+}
+
+func qrEncodingModeOption(name string) qrcode.EncodeOption {
+	switch name {
+	case "Numeric":
+		return qrcode.WithEncodingMode(qrcode.EncModeNumeric)
+	case "Alphanumeric":
+		return qrcode.WithEncodingMode(qrcode.EncModeAlphanumeric)
+	}
+
+	return qrcode.WithEncodingMode(qrcode.EncModeByte)
+}
+
+func isQRNumeric(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isQRAlphanumeric(c byte) bool {
+	return isQRNumeric(c) || c >= 'A' && c <= 'Z' || strings.IndexByte(" $%*+-./:", c) >= 0
+}
+
+// qrEncodingMode resolves "Auto" the way go-qrcode does, the narrowest mode
+// that holds every byte. A forced mode that can't hold the text is an error,
+// go-qrcode would encode it into garbage instead.
+func qrEncodingMode(text, mode string) (string, error) {
+	allNumeric, allAlphanumeric := true, true
+	for i := 0; i < len(text); i++ {
+		allNumeric = allNumeric && isQRNumeric(text[i])
+		allAlphanumeric = allAlphanumeric && isQRAlphanumeric(text[i])
+	}
+
+	switch mode {
+	case "Numeric":
+		if !allNumeric {
+			return "", errors.New("numeric mode only takes the digits 0-9")
+		}
+	case "Alphanumeric":
+		if !allAlphanumeric {
+			return "", errors.New("alphanumeric mode only takes 0-9, A-Z, space and $%*+-./:")
+		}
+	case "Byte":
+	default:
+		switch {
+		case allNumeric:
+			return "Numeric", nil
+		case allAlphanumeric:
+			return "Alphanumeric", nil
+		}
+		return "Byte", nil
+	}
+
+	return mode, nil
+}
+
+// QRInfo describes the code a text encodes to. Length and Capacity are
+// counted in characters of Mode, bytes for Byte mode.
+type QRInfo struct {
+	Version  int
+	Modules  int
+	Mode     string
+	Length   int
+	Capacity int
+}
+
+func (i QRInfo) Remaining() int {
+	return i.Capacity - i.Length
+}
+
+func (i QRInfo) String() string {
+	unit := "characters"
+	switch i.Mode {
+	case "Numeric":
+		unit = "digits"
+	case "Byte":
+		unit = "bytes"
+	}
+
+	return fmt.Sprintf("Version %d · %d×%d modules · %s · %d of %d %s left", i.Version, i.Modules, i.Modules, i.Mode, i.Remaining(), i.Capacity, unit)
+}
+
+// Info picks the smallest version holding text, but never below MinVersion.
+func (o QROptions) Info(text string) (QRInfo, error) {
+	mode, err := qrEncodingMode(text, o.EncodingMode)
+	if err != nil {
+		return QRInfo{}, err
+	}
+
+	info := QRInfo{Mode: mode, Length: len(text)}
+	column := slices.Index(listQREncodingMode, mode) - 1
+	level := qrErrorCorrection(o.ErrorCorrection)
+
+	for v := max(o.MinVersion, 1); v <= 40; v++ {
+		if capacity := qrCapacity[v-1][level][column]; capacity >= info.Length {
+			info.Version, info.Capacity = v, capacity
+			info.Modules = 17 + 4*v
+
+			return info, nil
+		}
+	}
+
+	largest := qrCapacity[39][level][column]
+
+	return QRInfo{}, fmt.Errorf("text is %d too long, %s mode holds %d at %s error correction", info.Length-largest, strings.ToLower(mode), largest, o.ErrorCorrection)
+}
+
+// encodeQR builds the code for text with the version Info chose.
+func (o QROptions) encodeQR(text string) (*qrcode.QRCode, error) {
+	info, err := o.Info(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.NewWith(text,
+		qrErrorCorrectionOption(o.ErrorCorrection),
+		qrEncodingModeOption(info.Mode),
+		qrcode.WithVersion(info.Version),
+	)
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yeqown/go-qrcode/v2"
+)
+
+var listQRExportFormat = []string{"PNG", "JPEG", "SVG"}
+
+const qrExportMaxSize = 8192
+
+func qrExportExtension(format string) string {
+	switch format {
+	case "JPEG":
+		return ".jpg"
+	case "SVG":
+		return ".svg"
+	}
+
+	return ".png"
+}
+
+// matrixWriter is a qrcode.Writer that keeps the module matrix instead of drawing it.
+type matrixWriter struct {
+	bitmap [][]bool
+}
+
+func (m *matrixWriter) Write(mat qrcode.Matrix) error {
+	m.bitmap = mat.Bitmap()
+	return nil
+}
+
+func (m *matrixWriter) Close() error {
+	return nil
+}
+
+// qrBitmap returns the dark/light modules of the QR for text, row by row.
+func qrBitmap(text string, opts QROptions) ([][]bool, error) {
+	qr, err := opts.encodeQR(text)
+	if err != nil {
+		return nil, err
+	}
+
+	mw := &matrixWriter{}
+	if err := qr.Save(mw); err != nil {
+		return nil, err
+	}
+
+	return mw.bitmap, nil
+}
+
+// QRSVGOptions mirrors what the raster writer draws: border and logo are in
+// pixels of a module ModulePixels wide, Size is the rendered width in pixels.
+// An empty Background leaves it transparent.
+type QRSVGOptions struct {
+	Size         int
+	ModulePixels int
+	BorderPixels int
+	Foreground   string
+	Background   string
+	GradientEnd  string
+	Shape        string
+	Finder       string
+	Logo         []byte
+}
+
+// qrFinderSize is the width in modules of the three corner finder patterns.
+const qrFinderSize = 7
+
+func isQRFinder(x, y, n int) bool {
+	return (x < qrFinderSize || x >= n-qrFinderSize) && y < qrFinderSize || x < qrFinderSize && y >= n-qrFinderSize
+}
+
+// svgNum drops float noise like 0.30000000000000004 from path data.
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// svgRoundedRect appends a closed rounded rectangle to an SVG path.
+func svgRoundedRect(path *strings.Builder, x, y, w, h, r float64) {
+	rr := svgNum(r)
+	fmt.Fprintf(path, "M%s %sh%sa%s %s 0 0 1 %s %sv%sa%s %s 0 0 1 -%s %sh-%sa%s %s 0 0 1 -%s -%sv-%sa%s %s 0 0 1 %s -%sz",
+		svgNum(x+r), svgNum(y), svgNum(w-2*r), rr, rr, rr, rr, svgNum(h-2*r), rr, rr, rr, rr, svgNum(w-2*r), rr, rr, rr, rr, svgNum(h-2*r), rr, rr, rr, rr)
+}
+
+// svgRoundedHole is svgRoundedRect wound the other way, so it cuts a hole
+// under both the nonzero and evenodd fill rules.
+func svgRoundedHole(path *strings.Builder, x, y, w, h, r float64) {
+	rr := svgNum(r)
+	fmt.Fprintf(path, "M%s %sa%s %s 0 0 0 -%s %sv%sa%s %s 0 0 0 %s %sh%sa%s %s 0 0 0 %s -%sv-%sa%s %s 0 0 0 -%s -%sz",
+		svgNum(x+r), svgNum(y), rr, rr, rr, rr, svgNum(h-2*r), rr, rr, rr, rr, svgNum(w-2*r), rr, rr, rr, rr, svgNum(h-2*r), rr, rr, rr, rr)
+}
+
+func svgCircle(path *strings.Builder, cx, cy, r float64) {
+	rr := svgNum(r)
+	fmt.Fprintf(path, "M%s %sa%s %s 0 1 0 %s 0a%s %s 0 1 0 -%s 0z", svgNum(cx-r), svgNum(cy), rr, rr, svgNum(2*r), rr, rr, svgNum(2*r))
+}
+
+// QRSVG renders the bitmap as vector paths, one unit per module, so it
+// scales to any print size. Rounded modules are drawn as rounded squares,
+// and a logo is embedded centred at its pixel size.
+func QRSVG(bitmap [][]bool, opts QRSVGOptions) ([]byte, error) {
+	module := float64(max(opts.ModulePixels, 1))
+	border := float64(opts.BorderPixels) / module
+	n := len(bitmap)
+	size := float64(n) + 2*border
+
+	style := func(x, y int) string {
+		if isQRFinder(x, y, n) {
+			if opts.Finder == "Rounded" {
+				return "FinderRounded"
+			}
+			return opts.Finder
+		}
+		return opts.Shape
+	}
+	isSquare := func(x, y int) bool {
+		s := style(x, y)
+		return bitmap[y][x] && (s == "Square" || s == "")
+	}
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		// one horizontal run per consecutive square modules keeps the file small
+		for x := 0; x < len(row); x++ {
+			if !isSquare(x, y) {
+				continue
+			}
+
+			start := x
+			for x < len(row) && isSquare(x, y) {
+				x++
+			}
+			fmt.Fprintf(&path, "M%s %sh%dv1h-%dz", svgNum(float64(start)+border), svgNum(float64(y)+border), x-start, x-start)
+		}
+
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			left, top := float64(x)+border, float64(y)+border
+			switch style(x, y) {
+			case "Circle":
+				svgCircle(&path, left+0.5, top+0.5, 0.5)
+			case "Dots":
+				svgCircle(&path, left+0.5, top+0.5, 0.35)
+			case "Rounded":
+				svgRoundedRect(&path, left, top, 1, 1, 0.35)
+			}
+		}
+	}
+
+	if opts.Finder == "Rounded" {
+		// ring and centre of each finder
+		for _, corner := range [][2]int{{0, 0}, {n - qrFinderSize, 0}, {0, n - qrFinderSize}} {
+			left, top := float64(corner[0])+border, float64(corner[1])+border
+			svgRoundedRect(&path, left, top, 7, 7, 1.75)
+			svgRoundedHole(&path, left+1, top+1, 5, 5, 1)
+			svgRoundedRect(&path, left+2, top+2, 3, 3, 0.75)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %g %g">`+"\n", opts.Size, opts.Size, size, size)
+
+	fill := opts.Foreground
+	if opts.GradientEnd != "" {
+		// same 45° direction as the raster writer's gradient
+		fmt.Fprintf(&out, `<defs><linearGradient id="fg" x1="0" y1="1" x2="1" y2="0"><stop offset="0" stop-color="%s"/><stop offset="1" stop-color="%s"/></linearGradient></defs>`+"\n",
+			opts.Foreground, opts.GradientEnd)
+		fill = "url(#fg)"
+	}
+
+	if opts.Background != "" {
+		fmt.Fprintf(&out, `<rect width="%g" height="%g" fill="%s"/>`+"\n", size, size, opts.Background)
+	}
+	fmt.Fprintf(&out, `<path d="%s" fill="%s"/>`+"\n", path.String(), fill)
+
+	if len(opts.Logo) > 0 {
+		logo, format, err := image.DecodeConfig(bytes.NewReader(opts.Logo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid logo: %w", err)
+		}
+
+		// the raster writer leaves out a logo bigger than the code, so do we
+		width, height := float64(logo.Width)/module, float64(logo.Height)/module
+		if width <= size && height <= size {
+			fmt.Fprintf(&out, `<image x="%g" y="%g" width="%g" height="%g" href="data:image/%s;base64,%s"/>`+"\n",
+				(size-width)/2, (size-height)/2, width, height, format, base64.StdEncoding.EncodeToString(opts.Logo))
+		}
+	}
+	out.WriteString("</svg>\n")
+
+	return out.Bytes(), nil
+}
+
+// svgOptions is the vector counterpart of the options a QR was drawn with.
+func (o QROptions) svgOptions(size, dimension int) (QRSVGOptions, error) {
+	if o.Halftone != "" {
+		return QRSVGOptions{}, errors.New("halftone images are raster only, save as PNG or JPEG instead")
+	}
+
+	svg := QRSVGOptions{
+		Size:         size,
+		ModulePixels: int(o.ModuleWidth),
+		BorderPixels: o.Border,
+		Foreground:   colorHex(o.Foreground),
+		Shape:        o.Shape,
+		Finder:       o.Finder,
+	}
+	if !o.Transparent {
+		svg.Background = colorHex(o.Background)
+	}
+	if o.Gradient {
+		svg.GradientEnd = colorHex(o.GradientEnd)
+	}
+
+	if logo, ok := findQRLogo(listQRLogos, o.Logo); ok {
+		img, err := renderQRLogo(logo, dimension*int(o.ModuleWidth), o.logoBackground())
+		if err != nil {
+			return QRSVGOptions{}, err
+		}
+		if svg.Logo, err = encodePNG(img); err != nil {
+			return QRSVGOptions{}, err
+		}
+	}
+
+	return svg, nil
+}
+
+// resizeQRImage scales with nearest neighbour, smoothing would blur the module
+// edges. Every module gets the same whole number of pixels, the code is centred
+// and the pixels left over widen the border.
+func resizeQRImage(src image.Image, size int, opts QROptions) (image.Image, error) {
+	bounds := src.Bounds()
+	module := max(int(opts.ModuleWidth), 1)
+	modules := (bounds.Dx() - 2*opts.Border) / module
+	if modules <= 0 {
+		return nil, errors.New("QR image doesn't match the options it was made with")
+	}
+
+	// the smallest size where the code and its border still get a pixel per module
+	minSize := (bounds.Dx() + module - 1) / module
+	if size < minSize {
+		return nil, fmt.Errorf("size must be at least %d pixels for this QR, got %d", minSize, size)
+	}
+
+	scale := size * module / bounds.Dx()
+	offset := (size - modules*scale) / 2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(opts.logoBackground()), image.Point{}, draw.Src)
+
+	// which source pixel an output pixel of the code falls on, staying inside its module
+	source := func(p int) int {
+		return opts.Border + p/scale*module + p%scale*module/scale
+	}
+	for y := 0; y < modules*scale; y++ {
+		sy := bounds.Min.Y + source(y)
+		for x := 0; x < modules*scale; x++ {
+			dst.Set(offset+x, offset+y, src.At(bounds.Min.X+source(x), sy))
+		}
+	}
+
+	return dst, nil
+}
+
+// EncodeQRImage re-encodes a QR rendered with opts as PNG or JPEG at size×size
+// pixels. JPEG has no alpha, transparent areas are flattened onto white.
+func EncodeQRImage(data []byte, format string, size int, opts QROptions) ([]byte, error) {
+	if size <= 0 || size > qrExportMaxSize {
+		return nil, fmt.Errorf("size must be between 1 and %d pixels, got %d", qrExportMaxSize, size)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := resizeQRImage(src, size, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	switch format {
+	case "PNG":
+		err = png.Encode(&out, img)
+	case "JPEG":
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+		err = jpeg.Encode(&out, flat, &jpeg.Options{Quality: 95})
+	default:
+		return nil, fmt.Errorf("unknown image format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
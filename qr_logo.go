@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fyne-io/oksvg"
+	"github.com/srwiley/rasterx"
+	xdraw "golang.org/x/image/draw"
+)
+
+const qrNoLogo = "No logo"
+
+// QRLogo is one entry of the logo library. The logo is scaled to fit
+// 1/SizeMultiplier of the code's width, Padding pixels of background around it.
+type QRLogo struct {
+	Name           string `json:"name"`
+	File           string `json:"file"`
+	SizeMultiplier int    `json:"sizeMultiplier"`
+	Padding        int    `json:"padding"`
+}
+
+// defaultQRLogos seeds the library on first run with the bundled assets.
+var defaultQRLogos = []QRLogo{
+	{Name: "Sebari", File: "assets/sebari_logo_qr_code.png", SizeMultiplier: 5, Padding: 4},
+	{Name: "Linkreator", File: "assets/linkreator_logo_qr_code.png", SizeMultiplier: 5, Padding: 4},
+}
+
+var listQRLogoExtension = []string{".png", ".jpg", ".jpeg", ".svg"}
+
+func qrLogoDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "sesterdamp", "qr_logos"), nil
+}
+
+func qrLogoLibraryPath() (string, error) {
+	dir, err := qrLogoDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "library.json"), nil
+}
+
+// loadQRLogos reads the library, a missing one starts from the bundled logos.
+func loadQRLogos() ([]QRLogo, error) {
+	path, err := qrLogoLibraryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return append([]QRLogo{}, defaultQRLogos...), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var logos []QRLogo
+	if err := json.Unmarshal(data, &logos); err != nil {
+		return nil, err
+	}
+
+	return logos, nil
+}
+
+func saveQRLogos(logos []QRLogo) error {
+	path, err := qrLogoLibraryPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(logos, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func findQRLogo(logos []QRLogo, name string) (QRLogo, bool) {
+	for _, logo := range logos {
+		if logo.Name == name {
+			return logo, true
+		}
+	}
+
+	return QRLogo{}, false
+}
+
+func (l QRLogo) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return errors.New("logo name must be fill")
+	}
+	if l.Name == qrNoLogo {
+		return fmt.Errorf("%q is reserved", qrNoLogo)
+	}
+	if l.SizeMultiplier < 2 || l.SizeMultiplier > 10 {
+		return fmt.Errorf("size must be between 1/2 and 1/10 of the code, got 1/%d", l.SizeMultiplier)
+	}
+	if l.Padding < 0 || l.Padding > 64 {
+		return fmt.Errorf("padding must be between 0 and 64 pixels, got %d", l.Padding)
+	}
+
+	return nil
+}
+
+// importQRLogo copies an uploaded file into the library folder, so the entry
+// keeps working after the original is moved or deleted.
+func importQRLogo(name string, data []byte, ext string) (string, error) {
+	ext = strings.ToLower(ext)
+	if !slices.Contains(listQRLogoExtension, ext) {
+		return "", fmt.Errorf("unsupported logo type %q, use PNG, JPEG or SVG", ext)
+	}
+	if _, err := decodeQRLogo(data, ext, 64); err != nil {
+		return "", fmt.Errorf("invalid logo: %w", err)
+	}
+
+	dir, err := qrLogoDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	base := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+
+	path := filepath.Join(dir, base+ext)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s_%d%s", base, i, ext))
+	}
+
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// removeQRLogoFile deletes an imported file, bundled assets are left alone.
+func removeQRLogoFile(logo QRLogo) error {
+	dir, err := qrLogoDir()
+	if err != nil {
+		return err
+	}
+	if filepath.Dir(logo.File) != dir {
+		return nil
+	}
+
+	err = os.Remove(logo.File)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// decodeQRLogo decodes PNG/JPEG, and rasterises SVG so it fits size×size.
+func decodeQRLogo(data []byte, ext string, size int) (img image.Image, err error) {
+	if strings.ToLower(ext) != ".svg" {
+		img, _, err = image.Decode(bytes.NewReader(data))
+		return img, err
+	}
+
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+		return nil, errors.New("SVG has no size, add a viewBox")
+	}
+
+	width, height := size, size
+	if aspect := icon.ViewBox.W / icon.ViewBox.H; aspect > 1 {
+		height = max(int(float64(size)/aspect), 1)
+	} else {
+		width = max(int(float64(size)*aspect), 1)
+	}
+
+	// oksvg panics on some malformed paths instead of returning an error
+	defer func() {
+		if r := recover(); r != nil {
+			img, err = nil, fmt.Errorf("cannot render SVG: %v", r)
+		}
+	}()
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	icon.SetTarget(0, 0, float64(width), float64(height))
+	scanner := rasterx.NewScannerGV(width, height, dst, dst.Bounds())
+	icon.Draw(rasterx.NewDasher(width, height, scanner), 1)
+
+	return dst, nil
+}
+
+// renderQRLogo scales the logo into 1/SizeMultiplier of qrPixels, keeping
+// its aspect, and frames it with Padding pixels of background. The padding
+// must leave room for the logo inside that share.
+func renderQRLogo(logo QRLogo, qrPixels int, background color.Color) (image.Image, error) {
+	data, err := os.ReadFile(logo.File)
+	if err != nil {
+		return nil, err
+	}
+
+	// the writer silently drops a logo bigger than its share, so say why instead
+	share := qrPixels / max(logo.SizeMultiplier, 1)
+	if share <= 2*logo.Padding {
+		return nil, fmt.Errorf("logo %s: %dpx padding leaves no room in a %dpx share of the code, lower the padding or use bigger modules", logo.Name, logo.Padding, share)
+	}
+	box := share - 2*logo.Padding
+
+	src, err := decodeQRLogo(data, filepath.Ext(logo.File), box)
+	if err != nil {
+		return nil, fmt.Errorf("logo %s: %w", logo.Name, err)
+	}
+
+	bounds := src.Bounds()
+	width, height := box, box
+	if bounds.Dx() > bounds.Dy() {
+		height = max(box*bounds.Dy()/bounds.Dx(), 1)
+	} else {
+		width = max(box*bounds.Dx()/bounds.Dy(), 1)
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, width+2*logo.Padding, height+2*logo.Padding))
+	if logo.Padding > 0 {
+		xdraw.Draw(out, out.Bounds(), image.NewUniform(background), image.Point{}, xdraw.Src)
+	}
+	xdraw.CatmullRom.Scale(out, image.Rect(logo.Padding, logo.Padding, logo.Padding+width, logo.Padding+height), src, bounds, xdraw.Over, nil)
+
+	return out, nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,852 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const qrPayloadText = "Text"
+
+// QRPayloadField is one form field of a template. Options makes it a select,
+// Check a checkbox holding "true" or "".
+type QRPayloadField struct {
+	Key         string
+	Label       string
+	PlaceHolder string
+	Options     []string
+	Check       bool
+	Multiline   bool
+}
+
+// QRPayload assembles a structured payload from form values and reads one
+// back. Prefixes are what a payload of this kind starts with, case-insensitive.
+type QRPayload struct {
+	Name     string
+	Prefixes []string
+	Fields   []QRPayloadField
+	Build    func(v map[string]string) (string, error)
+	Parse    func(payload string) (map[string]string, error)
+}
+
+var listWiFiSecurity = []string{"WPA/WPA2", "WEP", "None"}
+
+const (
+	qrEventDateTimeLayout = "2006-01-02 15:04"
+	qrEventDateLayout     = "2006-01-02"
+)
+
+var listQRPayloads = []QRPayload{
+	{
+		Name:     "Wi-Fi",
+		Prefixes: []string{"WIFI:"},
+		Fields: []QRPayloadField{
+			{Key: "ssid", Label: "Network Name", PlaceHolder: "SSID"},
+			{Key: "security", Label: "Security", Options: listWiFiSecurity},
+			{Key: "password", Label: "Password"},
+			{Key: "hidden", Label: "Hidden network", Check: true},
+		},
+		Build: buildWiFiPayload,
+		Parse: parseWiFiPayload,
+	},
+	{
+		Name:     "vCard",
+		Prefixes: []string{"BEGIN:VCARD"},
+		Fields: []QRPayloadField{
+			{Key: "first", Label: "First Name"},
+			{Key: "last", Label: "Last Name"},
+			{Key: "org", Label: "Organization"},
+			{Key: "title", Label: "Job Title"},
+			{Key: "phone", Label: "Phone", PlaceHolder: "+62 812 3456 7890"},
+			{Key: "email", Label: "Email"},
+			{Key: "url", Label: "Website", PlaceHolder: "https://"},
+			{Key: "address", Label: "Address"},
+			{Key: "note", Label: "Note", Multiline: true},
+		},
+		Build: buildVCardPayload,
+		Parse: parseVCardPayload,
+	},
+	{
+		Name:     "MeCard",
+		Prefixes: []string{"MECARD:"},
+		Fields: []QRPayloadField{
+			{Key: "first", Label: "First Name"},
+			{Key: "last", Label: "Last Name"},
+			{Key: "org", Label: "Organization"},
+			{Key: "phone", Label: "Phone", PlaceHolder: "+62 812 3456 7890"},
+			{Key: "email", Label: "Email"},
+			{Key: "url", Label: "Website", PlaceHolder: "https://"},
+			{Key: "address", Label: "Address"},
+			{Key: "note", Label: "Note", Multiline: true},
+		},
+		Build: buildMeCardPayload,
+		Parse: parseMeCardPayload,
+	},
+	{
+		Name:     "Email",
+		Prefixes: []string{"mailto:"},
+		Fields: []QRPayloadField{
+			{Key: "to", Label: "To", PlaceHolder: "name@example.com"},
+			{Key: "cc", Label: "CC"},
+			{Key: "subject", Label: "Subject"},
+			{Key: "body", Label: "Body", Multiline: true},
+		},
+		Build: buildMailtoPayload,
+		Parse: parseMailtoPayload,
+	},
+	{
+		Name:     "SMS",
+		Prefixes: []string{"SMSTO:", "sms:"},
+		Fields: []QRPayloadField{
+			{Key: "phone", Label: "Phone", PlaceHolder: "+62 812 3456 7890"},
+			{Key: "message", Label: "Message", Multiline: true},
+		},
+		Build: buildSMSPayload,
+		Parse: parseSMSPayload,
+	},
+	{
+		Name:     "Phone",
+		Prefixes: []string{"tel:"},
+		Fields: []QRPayloadField{
+			{Key: "phone", Label: "Phone", PlaceHolder: "+62 812 3456 7890"},
+		},
+		Build: buildTelPayload,
+		Parse: parseTelPayload,
+	},
+	{
+		Name:     "Geo Location",
+		Prefixes: []string{"geo:"},
+		Fields: []QRPayloadField{
+			{Key: "lat", Label: "Latitude", PlaceHolder: "-6.2088"},
+			{Key: "lon", Label: "Longitude", PlaceHolder: "106.8456"},
+			{Key: "label", Label: "Label"},
+		},
+		Build: buildGeoPayload,
+		Parse: parseGeoPayload,
+	},
+	{
+		Name:     "Calendar Event",
+		Prefixes: []string{"BEGIN:VEVENT", "BEGIN:VCALENDAR"},
+		Fields: []QRPayloadField{
+			{Key: "summary", Label: "Title"},
+			{Key: "start", Label: "Start", PlaceHolder: qrEventDateTimeLayout},
+			{Key: "end", Label: "End", PlaceHolder: qrEventDateTimeLayout},
+			{Key: "allDay", Label: "All day", Check: true},
+			{Key: "location", Label: "Location"},
+			{Key: "description", Label: "Description", Multiline: true},
+		},
+		Build: buildEventPayload,
+		Parse: parseEventPayload,
+	},
+}
+
+// listQRPayloadType is plain text followed by every template.
+var listQRPayloadType = func() []string {
+	names := []string{qrPayloadText}
+	for _, p := range listQRPayloads {
+		names = append(names, p.Name)
+	}
+
+	return names
+}()
+
+func findQRPayload(name string) (QRPayload, bool) {
+	for _, p := range listQRPayloads {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return QRPayload{}, false
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// detectQRPayload finds the template an existing payload was made with and reads it.
+func detectQRPayload(text string) (QRPayload, map[string]string, error) {
+	text = strings.TrimSpace(text)
+	for _, p := range listQRPayloads {
+		for _, prefix := range p.Prefixes {
+			if !hasPrefixFold(text, prefix) {
+				continue
+			}
+
+			values, err := p.Parse(text)
+			if err != nil {
+				return QRPayload{}, nil, fmt.Errorf("invalid %s payload: %w", p.Name, err)
+			}
+			return p, values, nil
+		}
+	}
+
+	return QRPayload{}, nil, errors.New("not a known payload, templates read WIFI:, BEGIN:VCARD, MECARD:, mailto:, SMSTO:, tel:, geo: and BEGIN:VEVENT")
+}
+
+// escapeQR backslash-escapes the backslash and every byte of special.
+func escapeQR(s, special string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || strings.IndexByte(special, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// splitEscaped splits on sep where it isn't backslash-escaped, escapes are kept.
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescapeQR drops the backslash before any character.
+func unescapeQR(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// qrFields reads KEY:value pairs separated by unescaped ";", as Wi-Fi and
+// MeCard payloads are. Keys come back upper-case, values still escaped.
+func qrFields(body string) map[string]string {
+	fields := map[string]string{}
+	for _, part := range splitEscaped(body, ';') {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if _, seen := fields[key]; !seen {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
+
+var wifiEscapes = `;,:"`
+
+func buildWiFiPayload(v map[string]string) (string, error) {
+	if v["ssid"] == "" {
+		return "", errors.New("network name must be fill")
+	}
+
+	var b strings.Builder
+	b.WriteString("WIFI:")
+	switch v["security"] {
+	case "None":
+		b.WriteString("T:nopass;")
+	case "WEP":
+		if err := validWEPKey(v["password"]); err != nil {
+			return "", err
+		}
+		b.WriteString("T:WEP;")
+	default:
+		// 64 hex digits is a raw pre-shared key instead of a passphrase
+		if n := len(v["password"]); (n < 8 || n > 63) && !(n == 64 && hexKey.MatchString(v["password"])) {
+			return "", fmt.Errorf("WPA password must be 8 to 63 characters, got %d", n)
+		}
+		b.WriteString("T:WPA;")
+	}
+	fmt.Fprintf(&b, "S:%s;", escapeQR(v["ssid"], wifiEscapes))
+	if v["security"] != "None" {
+		fmt.Fprintf(&b, "P:%s;", escapeQR(v["password"], wifiEscapes))
+	}
+	if v["hidden"] == "true" {
+		b.WriteString("H:true;")
+	}
+	b.WriteString(";")
+
+	return b.String(), nil
+}
+
+var hexKey = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// validWEPKey accepts 40/104-bit keys, as 5/13 characters or 10/26 hex digits.
+func validWEPKey(key string) error {
+	switch len(key) {
+	case 5, 13:
+		return nil
+	case 10, 26:
+		if hexKey.MatchString(key) {
+			return nil
+		}
+	}
+
+	return errors.New("WEP key must be 5 or 13 characters, or 10 or 26 hex digits")
+}
+
+func parseWiFiPayload(payload string) (map[string]string, error) {
+	fields := qrFields(payload[len("WIFI:"):])
+	if fields["S"] == "" {
+		return nil, errors.New("missing network name (S:)")
+	}
+
+	v := map[string]string{
+		"ssid":     unescapeQR(fields["S"]),
+		"password": unescapeQR(fields["P"]),
+		"security": "WPA/WPA2",
+	}
+	switch strings.ToUpper(fields["T"]) {
+	case "WEP":
+		v["security"] = "WEP"
+	case "NOPASS":
+		v["security"], v["password"] = "None", ""
+	case "":
+		if fields["P"] == "" {
+			v["security"] = "None"
+		}
+	}
+	if strings.EqualFold(fields["H"], "true") {
+		v["hidden"] = "true"
+	}
+
+	return v, nil
+}
+
+// escapeVText escapes a vCard/iCalendar TEXT value.
+func escapeVText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(escapeQR(s, ";,"), "\n", `\n`)
+}
+
+// unescapeVText is the inverse of escapeVText, "\N" is read as a newline too.
+func unescapeVText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// vProperty is one content line of a vCard or iCalendar object.
+type vProperty struct {
+	Name   string
+	Params string
+	Value  string
+}
+
+// vProperties unfolds the content lines and splits each into name, params and
+// the still escaped value. The first of a repeated property wins.
+func vProperties(payload string) map[string]vProperty {
+	payload = strings.ReplaceAll(payload, "\r\n", "\n")
+	payload = strings.ReplaceAll(payload, "\n ", "")
+	payload = strings.ReplaceAll(payload, "\n\t", "")
+
+	props := map[string]vProperty{}
+	for _, line := range strings.Split(payload, "\n") {
+		head, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(head, ";")
+
+		// a grouped property like item1.EMAIL
+		if _, after, grouped := strings.Cut(name, "."); grouped {
+			name = after
+		}
+
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if _, seen := props[name]; !seen {
+			props[name] = vProperty{Name: name, Params: strings.ToUpper(params), Value: value}
+		}
+	}
+
+	return props
+}
+
+func fullName(v map[string]string) string {
+	return strings.TrimSpace(v["first"] + " " + v["last"])
+}
+
+func buildVCardPayload(v map[string]string) (string, error) {
+	if fullName(v) == "" && v["org"] == "" {
+		return "", errors.New("name or organization must be fill")
+	}
+
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		fmt.Sprintf("N:%s;%s;;;", escapeVText(v["last"]), escapeVText(v["first"])),
+	}
+	// FN is required, a company card falls back to the organization
+	if name := fullName(v); name != "" {
+		lines = append(lines, "FN:"+escapeVText(name))
+	} else {
+		lines = append(lines, "FN:"+escapeVText(v["org"]))
+	}
+	if v["org"] != "" {
+		lines = append(lines, "ORG:"+escapeVText(v["org"]))
+	}
+	if v["title"] != "" {
+		lines = append(lines, "TITLE:"+escapeVText(v["title"]))
+	}
+	if v["phone"] != "" {
+		phone, err := normalizePhone(v["phone"])
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, "TEL;TYPE=CELL:"+phone)
+	}
+	if v["email"] != "" {
+		if err := validEmails(v["email"]); err != nil {
+			return "", err
+		}
+		lines = append(lines, "EMAIL:"+v["email"])
+	}
+	if v["url"] != "" {
+		lines = append(lines, "URL:"+v["url"])
+	}
+	if v["address"] != "" {
+		lines = append(lines, fmt.Sprintf("ADR:;;%s;;;;", escapeVText(v["address"])))
+	}
+	if v["note"] != "" {
+		lines = append(lines, "NOTE:"+escapeVText(v["note"]))
+	}
+	lines = append(lines, "END:VCARD")
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func parseVCardPayload(payload string) (map[string]string, error) {
+	props := vProperties(payload)
+	if _, ok := props["END"]; !ok {
+		return nil, errors.New("missing END:VCARD")
+	}
+
+	v := map[string]string{}
+	if n, ok := props["N"]; ok {
+		parts := splitEscaped(n.Value, ';')
+		v["last"] = unescapeVText(parts[0])
+		if len(parts) > 1 {
+			v["first"] = unescapeVText(parts[1])
+		}
+	} else if fn, ok := props["FN"]; ok {
+		v["first"] = unescapeVText(fn.Value)
+	}
+
+	// ORG is organization;unit, ADR is seven parts starting with PO box
+	if org, ok := props["ORG"]; ok {
+		v["org"] = unescapeVText(splitEscaped(org.Value, ';')[0])
+	}
+	if adr, ok := props["ADR"]; ok {
+		var parts []string
+		for _, part := range splitEscaped(adr.Value, ';') {
+			if part = strings.TrimSpace(unescapeVText(part)); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		v["address"] = strings.Join(parts, ", ")
+	}
+
+	for key, name := range map[string]string{"title": "TITLE", "phone": "TEL", "email": "EMAIL", "url": "URL", "note": "NOTE"} {
+		if prop, ok := props[name]; ok {
+			v[key] = unescapeVText(prop.Value)
+		}
+	}
+	v["phone"] = strings.TrimPrefix(v["phone"], "tel:")
+
+	return v, nil
+}
+
+var meCardEscapes = `;,:"`
+
+func buildMeCardPayload(v map[string]string) (string, error) {
+	if fullName(v) == "" {
+		return "", errors.New("name must be fill")
+	}
+
+	var b strings.Builder
+	b.WriteString("MECARD:N:")
+	// a lone part reads back as the first name, so a last name keeps its comma
+	b.WriteString(escapeQR(v["last"], meCardEscapes))
+	if v["last"] != "" {
+		b.WriteString(",")
+	}
+	b.WriteString(escapeQR(v["first"], meCardEscapes) + ";")
+
+	if v["org"] != "" {
+		fmt.Fprintf(&b, "ORG:%s;", escapeQR(v["org"], meCardEscapes))
+	}
+	if v["phone"] != "" {
+		phone, err := normalizePhone(v["phone"])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "TEL:%s;", phone)
+	}
+	if v["email"] != "" {
+		if err := validEmails(v["email"]); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "EMAIL:%s;", escapeQR(v["email"], meCardEscapes))
+	}
+	for _, field := range []struct{ key, name string }{{"url", "URL"}, {"address", "ADR"}, {"note", "NOTE"}} {
+		if v[field.key] != "" {
+			fmt.Fprintf(&b, "%s:%s;", field.name, escapeQR(v[field.key], meCardEscapes))
+		}
+	}
+	b.WriteString(";")
+
+	return b.String(), nil
+}
+
+func parseMeCardPayload(payload string) (map[string]string, error) {
+	fields := qrFields(payload[len("MECARD:"):])
+	if fields["N"] == "" {
+		return nil, errors.New("missing name (N:)")
+	}
+
+	v := map[string]string{}
+	names := splitEscaped(fields["N"], ',')
+	if len(names) > 1 {
+		v["last"], v["first"] = unescapeQR(names[0]), unescapeQR(names[1])
+	} else {
+		v["first"] = unescapeQR(names[0])
+	}
+	for key, name := range map[string]string{"org": "ORG", "phone": "TEL", "email": "EMAIL", "url": "URL", "address": "ADR", "note": "NOTE"} {
+		v[key] = unescapeQR(fields[name])
+	}
+
+	return v, nil
+}
+
+// validEmails checks a comma separated list of bare addresses, a display
+// name like "Name <a@b.c>" doesn't belong in a payload.
+func validEmails(list string) error {
+	for _, addr := range strings.Split(list, ",") {
+		addr = strings.TrimSpace(addr)
+		if parsed, err := mail.ParseAddress(addr); err != nil || parsed.Address != addr {
+			return fmt.Errorf("invalid email: %v", addr)
+		}
+	}
+
+	return nil
+}
+
+var phoneChars = regexp.MustCompile(`^\+?[0-9]{3,15}$`)
+
+// normalizePhone drops the usual separators and checks what's left is a
+// number, optionally with a leading "+".
+func normalizePhone(phone string) (string, error) {
+	cleaned := strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(phone)
+	if !phoneChars.MatchString(cleaned) {
+		return "", fmt.Errorf("invalid phone: %v", phone)
+	}
+
+	return cleaned, nil
+}
+
+// mailtoEscape percent-encodes a mailto header value, spaces as %20 since
+// RFC 6068 reads "+" literally, line breaks as CRLF.
+func mailtoEscape(s string) string {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func buildMailtoPayload(v map[string]string) (string, error) {
+	if v["to"] == "" {
+		return "", errors.New("recipient must be fill")
+	}
+	for _, key := range []string{"to", "cc"} {
+		if v[key] == "" {
+			continue
+		}
+		if err := validEmails(v[key]); err != nil {
+			return "", err
+		}
+	}
+
+	var to []string
+	for _, addr := range strings.Split(v["to"], ",") {
+		to = append(to, url.PathEscape(strings.TrimSpace(addr)))
+	}
+
+	var query []string
+	for _, key := range []string{"cc", "subject", "body"} {
+		if v[key] != "" {
+			query = append(query, key+"="+mailtoEscape(v[key]))
+		}
+	}
+
+	payload := "mailto:" + strings.Join(to, ",")
+	if len(query) > 0 {
+		payload += "?" + strings.Join(query, "&")
+	}
+
+	return payload, nil
+}
+
+func parseMailtoPayload(payload string) (map[string]string, error) {
+	to, query, _ := strings.Cut(payload[len("mailto:"):], "?")
+
+	var err error
+	v := map[string]string{}
+	if v["to"], err = url.PathUnescape(to); err != nil {
+		return nil, err
+	}
+
+	for _, pair := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		key = strings.ToLower(key)
+		if key != "cc" && key != "subject" && key != "body" {
+			continue
+		}
+
+		// PathUnescape leaves "+" alone, unlike url.ParseQuery
+		value, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		v[key] = strings.ReplaceAll(value, "\r\n", "\n")
+	}
+
+	return v, nil
+}
+
+func buildSMSPayload(v map[string]string) (string, error) {
+	phone, err := normalizePhone(v["phone"])
+	if err != nil {
+		return "", err
+	}
+
+	// everything after the second ":" is the message, it needs no escaping
+	return "SMSTO:" + phone + ":" + v["message"], nil
+}
+
+func parseSMSPayload(payload string) (map[string]string, error) {
+	if hasPrefixFold(payload, "SMSTO:") {
+		phone, message, _ := strings.Cut(payload[len("SMSTO:"):], ":")
+		return map[string]string{"phone": phone, "message": message}, nil
+	}
+
+	// sms:+62812?body=... and the iOS variant sms:+62812&body=...
+	rest := payload[len("sms:"):]
+	phone, query, _ := strings.Cut(rest, "?")
+	if i := strings.Index(phone, "&"); i >= 0 {
+		phone, query = phone[:i], phone[i+1:]
+	}
+
+	v := map[string]string{"phone": phone}
+	for _, pair := range strings.Split(query, "&") {
+		if key, value, _ := strings.Cut(pair, "="); strings.EqualFold(key, "body") {
+			body, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, err
+			}
+			v["message"] = body
+		}
+	}
+
+	return v, nil
+}
+
+func buildTelPayload(v map[string]string) (string, error) {
+	phone, err := normalizePhone(v["phone"])
+	if err != nil {
+		return "", err
+	}
+
+	return "tel:" + phone, nil
+}
+
+func parseTelPayload(payload string) (map[string]string, error) {
+	return map[string]string{"phone": payload[len("tel:"):]}, nil
+}
+
+func parseCoordinate(text, name string, limit float64) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || value < -limit || value > limit {
+		return 0, fmt.Errorf("%s must be a number between -%g and %g", name, limit, limit)
+	}
+
+	return value, nil
+}
+
+func buildGeoPayload(v map[string]string) (string, error) {
+	lat, err := parseCoordinate(v["lat"], "latitude", 90)
+	if err != nil {
+		return "", err
+	}
+	lon, err := parseCoordinate(v["lon"], "longitude", 180)
+	if err != nil {
+		return "", err
+	}
+
+	payload := "geo:" + strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+	if v["label"] != "" {
+		// Android maps read the label from a search query
+		payload += "?q=" + strings.ReplaceAll(url.QueryEscape(v["label"]), "+", "%20")
+	}
+
+	return payload, nil
+}
+
+func parseGeoPayload(payload string) (map[string]string, error) {
+	coords, query, _ := strings.Cut(payload[len("geo:"):], "?")
+	// drop the ;crs= and ;u= parameters of RFC 5870
+	coords, _, _ = strings.Cut(coords, ";")
+
+	parts := strings.Split(coords, ",")
+	if len(parts) < 2 {
+		return nil, errors.New("expected geo:latitude,longitude")
+	}
+	if _, err := parseCoordinate(parts[0], "latitude", 90); err != nil {
+		return nil, err
+	}
+	if _, err := parseCoordinate(parts[1], "longitude", 180); err != nil {
+		return nil, err
+	}
+
+	v := map[string]string{"lat": parts[0], "lon": parts[1]}
+	for _, pair := range strings.Split(query, "&") {
+		if key, value, _ := strings.Cut(pair, "="); key == "q" {
+			label, err := url.QueryUnescape(value)
+			if err != nil {
+				return nil, err
+			}
+			v["label"] = label
+		}
+	}
+
+	return v, nil
+}
+
+func buildEventPayload(v map[string]string) (string, error) {
+	if strings.TrimSpace(v["summary"]) == "" {
+		return "", errors.New("title must be fill")
+	}
+
+	layout, format, param := qrEventDateTimeLayout, "20060102T150405", ""
+	if v["allDay"] == "true" {
+		layout, format, param = qrEventDateLayout, "20060102", ";VALUE=DATE"
+	}
+
+	start, err := time.ParseInLocation(layout, strings.TrimSpace(v["start"]), time.Local)
+	if err != nil {
+		return "", fmt.Errorf("invalid start: %v, use %s", v["start"], layout)
+	}
+
+	lines := []string{"BEGIN:VEVENT", "SUMMARY:" + escapeVText(v["summary"]), "DTSTART" + param + ":" + start.Format(format)}
+
+	if strings.TrimSpace(v["end"]) != "" {
+		end, err := time.ParseInLocation(layout, strings.TrimSpace(v["end"]), time.Local)
+		if err != nil {
+			return "", fmt.Errorf("invalid end: %v, use %s", v["end"], layout)
+		}
+		if end.Before(start) {
+			return "", errors.New("end must not be before start")
+		}
+		// an all-day DTEND is exclusive, the form shows the last day
+		if v["allDay"] == "true" {
+			end = end.AddDate(0, 0, 1)
+		}
+		lines = append(lines, "DTEND"+param+":"+end.Format(format))
+	}
+
+	if v["location"] != "" {
+		lines = append(lines, "LOCATION:"+escapeVText(v["location"]))
+	}
+	if v["description"] != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeVText(v["description"]))
+	}
+	lines = append(lines, "END:VEVENT")
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseEventTime reads DATE, local DATE-TIME and UTC DATE-TIME values, UTC is
+// shown in local time. TZID is ignored, the time is taken as written.
+func parseEventTime(prop vProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+	if strings.Contains(prop.Params, "VALUE=DATE") && !strings.Contains(prop.Params, "VALUE=DATE-TIME") || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.Local(), false, err
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, time.Local)
+	return t, false, err
+}
+
+func parseEventPayload(payload string) (map[string]string, error) {
+	// skip what a VCALENDAR has before the event, a VTIMEZONE has its own DTSTART
+	if i := strings.Index(strings.ToUpper(payload), "BEGIN:VEVENT"); i > 0 {
+		payload = payload[i:]
+	}
+
+	props := vProperties(payload)
+	start, ok := props["DTSTART"]
+	if !ok {
+		return nil, errors.New("missing DTSTART")
+	}
+
+	startTime, allDay, err := parseEventTime(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DTSTART: %v", start.Value)
+	}
+
+	layout := qrEventDateTimeLayout
+	v := map[string]string{}
+	if allDay {
+		layout = qrEventDateLayout
+		v["allDay"] = "true"
+	}
+	v["start"] = startTime.Format(layout)
+
+	if end, ok := props["DTEND"]; ok {
+		endTime, _, err := parseEventTime(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTEND: %v", end.Value)
+		}
+		if allDay {
+			endTime = endTime.AddDate(0, 0, -1)
+		}
+		v["end"] = endTime.Format(layout)
+	}
+
+	for key, name := range map[string]string{"summary": "SUMMARY", "location": "LOCATION", "description": "DESCRIPTION"} {
+		if prop, ok := props[name]; ok {
+			v[key] = unescapeVText(prop.Value)
+		}
+	}
+
+	return v, nil
+}
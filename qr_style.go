@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yeqown/go-qrcode/writer/standard"
+	"github.com/yeqown/go-qrcode/writer/standard/shapes"
+)
+
+var listQRModuleShape = []string{"Square", "Circle", "Dots", "Rounded"}
+
+var listQRFinderStyle = []string{"Square", "Rounded", "Circle"}
+
+// QROptions is everything generateQR encodes and draws with. Colours are used
+// as given, Transparent drops the background (the logo padding included).
+type QROptions struct {
+	Logo        string
+	ModuleWidth uint8
+	Border      int
+
+	ErrorCorrection string
+	EncodingMode    string
+	MinVersion      int
+
+	Foreground  color.NRGBA
+	Background  color.NRGBA
+	Transparent bool
+	Gradient    bool
+	GradientEnd color.NRGBA
+	Shape       string
+	Finder      string
+	Halftone    string
+}
+
+var defaultQROptions = QROptions{
+	Logo:        qrNoLogo,
+	ModuleWidth: 15,
+
+	ErrorCorrection: "Quartile (25%)",
+	EncodingMode:    "Auto",
+
+	Foreground:  color.NRGBA{A: 0xff},
+	Background:  color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	GradientEnd: color.NRGBA{R: 0x1e, G: 0x40, B: 0xaf, A: 0xff},
+	Shape:       "Square",
+	Finder:      "Square",
+}
+
+func qrModuleShape(name string) func(ctx *standard.DrawContext) {
+	switch name {
+	case "Circle":
+		return shapes.CircleBlocks(1)
+	case "Dots":
+		return shapes.CircleBlocks(0.7)
+	case "Rounded":
+		return shapes.LiquidBlock()
+	}
+
+	return shapes.SquareBlocks(1)
+}
+
+func qrFinderShape(name string) func(ctx *standard.DrawContext) {
+	switch name {
+	case "Rounded":
+		return shapes.RoundedFinder()
+	case "Circle":
+		return shapes.CircleBlocks(1)
+	}
+
+	return shapes.SquareFinder()
+}
+
+func toRGBA(c color.NRGBA) color.RGBA {
+	r, g, b, a := c.RGBA()
+	return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+}
+
+// imageOptions turns the style into go-qrcode writer options.
+func (o QROptions) imageOptions() []standard.ImageOption {
+	options := []standard.ImageOption{
+		standard.WithFgColor(toRGBA(o.Foreground)),
+		standard.WithBgColor(toRGBA(o.Background)),
+		standard.WithCustomShape(shapes.Assemble(qrFinderShape(o.Finder), qrModuleShape(o.Shape))),
+	}
+	if o.Transparent {
+		options = append(options, standard.WithBgTransparent())
+	}
+	if o.Gradient {
+		options = append(options, standard.WithFgGradient(standard.NewGradient(45,
+			standard.ColorStop{T: 0, Color: toRGBA(o.Foreground)},
+			standard.ColorStop{T: 1, Color: toRGBA(o.GradientEnd)},
+		)))
+	}
+	if o.Halftone != "" {
+		options = append(options, standard.WithHalftone(o.Halftone))
+	}
+
+	return options
+}
+
+// logoBackground is what the logo padding is filled with.
+func (o QROptions) logoBackground() color.Color {
+	if o.Transparent {
+		return color.Transparent
+	}
+
+	return o.Background
+}
+
+// parseColorHex accepts #rgb, #rrggbb and #rrggbbaa, the "#" is optional.
+func parseColorHex(text string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(text), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("invalid color: %v", text)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color: %v", text)
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// colorHex is the inverse of parseColorHex, alpha is only written when not opaque.
+func colorHex(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// relativeLuminance follows WCAG 2, alpha is blended onto white.
+func relativeLuminance(c color.NRGBA) float64 {
+	channel := func(v uint8) float64 {
+		a := float64(c.A) / 255
+		s := (float64(v)*a + 255*(1-a)) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+func contrastRatio(a, b color.NRGBA) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// qrMinContrast is below what phone scanners read reliably in practice.
+const qrMinContrast = 4.0
+
+// CheckContrast returns a warning when the code may not scan, nil when it looks fine.
+func (o QROptions) CheckContrast() error {
+	background := o.Background
+	if o.Transparent {
+		// scanners see whatever the code is placed on, assume paper
+		background = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	}
+
+	foregrounds := []color.NRGBA{o.Foreground}
+	if o.Gradient {
+		foregrounds = append(foregrounds, o.GradientEnd)
+	}
+
+	var problems []string
+	inverted := false
+	for _, fg := range foregrounds {
+		if ratio := contrastRatio(fg, background); ratio < qrMinContrast {
+			problems = append(problems, fmt.Sprintf("contrast of %s on %s is only %.1f:1, aim for %.0f:1 or more", colorHex(fg), colorHex(background), ratio, qrMinContrast))
+		}
+		inverted = inverted || relativeLuminance(fg) > relativeLuminance(background)
+	}
+	if inverted {
+		problems = append(problems, "light modules on a dark background are inverted, many scanners can't read them")
+	}
+	if o.Transparent {
+		problems = append(problems, "transparent background only scans on a light surface")
+	}
+	if o.Halftone != "" {
+		problems = append(problems, "halftone images lower contrast, test the result with a phone")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(problems, "; "))
+}
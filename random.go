@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	mrand "math/rand/v2"
+	"time"
+)
+
+// randomSource feeds randomInt and randomBytes. It is crypto/rand except while
+// a deterministic batch is generated, see useRandomSource. Only touch it from
+// the UI goroutine, where the generators run. Secrets (OTP secrets, key pairs,
+// password salts, the OTP vault) read crypto/rand directly and never use it.
+var randomSource io.Reader = rand.Reader
+
+// randomClock is what time-based IDs and fake dates read the time from. It is
+// time.Now except in a deterministic batch, where it is a seededClock.
+var randomClock = time.Now
+
+// isSeeded reports whether a deterministic batch is being generated.
+func isSeeded() bool {
+	return randomSource != rand.Reader
+}
+
+// seededSource returns a ChaCha8 stream keyed by the SHA-256 of seed. The same
+// seed always yields the same bytes, so it must never be used for secrets.
+func seededSource(seed string) io.Reader {
+	return mrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+}
+
+// seededClock starts at a moment in 2024 picked by seed and moves one
+// millisecond per reading, so time-based IDs repeat with the seed and stay ordered.
+func seededClock(seed string) func() time.Time {
+	sum := sha256.Sum256([]byte("clock:" + seed))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).
+		Add(time.Duration(binary.BigEndian.Uint64(sum[:8])%(365*24*3600*1000)) * time.Millisecond)
+
+	ticks := 0
+	return func() time.Time {
+		ticks++
+		return start.Add(time.Duration(ticks) * time.Millisecond)
+	}
+}
+
+// useRandomSource makes the generators draw from src and read the time from
+// clock until restore is called.
+func useRandomSource(src io.Reader, clock func() time.Time) (restore func()) {
+	previousSource, previousClock := randomSource, randomClock
+	randomSource, randomClock = src, clock
+
+	return func() {
+		randomSource, randomClock = previousSource, previousClock
+	}
+}
+
+// newSeed suggests a fresh seed, drawn from crypto/rand regardless of the current source.
+func newSeed() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encodeBase62(b, 11), nil
+}
@@ -0,0 +1,596 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// strength estimation follows the zxcvbn idea: find every known pattern in the
+// password, then pick the cheapest way for an attacker to cover it with patterns
+// and brute force.
+
+const strengthMaxLength = 100
+
+var listCommonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "1234567", "111111", "1234567890", "123123",
+	"abc123", "1234", "password1", "iloveyou", "1q2w3e4r", "000000", "qwerty123", "zaq12wsx", "dragon", "sunshine",
+	"princess", "letmein", "654321", "monkey", "27653", "1qaz2wsx", "123321", "qwertyuiop", "superman", "asdfghjkl",
+	"football", "baseball", "welcome", "admin", "login", "master", "hello", "freedom", "whatever", "qazwsx",
+	"trustno1", "starwars", "passw0rd", "shadow", "michael", "jennifer", "charlie", "jordan", "hunter", "killer",
+	"batman", "secret", "summer", "winter", "spring", "autumn", "flower", "cookie", "soccer", "pokemon",
+	"computer", "internet", "samsung", "google", "facebook", "mustang", "access", "ninja", "azerty", "solo",
+	"loveme", "lovely", "angel", "tigger", "buster", "pepper", "ginger", "cheese", "banana", "orange",
+	"chocolate", "blessed", "family", "forever", "jesus", "matrix", "thomas", "daniel", "andrew", "robert",
+	"rahasia", "sayang", "bismillah", "indonesia", "jakarta", "cinta", "kucing", "merdeka", "garuda", "bandung",
+}
+
+var strengthDictionaries = map[string]map[string]int{
+	"common passwords": rankedDictionary(listCommonPasswords),
+	"english words":    rankedDictionary(effLargeWordlist),
+}
+
+var l33tTable = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+var keyboardShifted = map[rune]rune{
+	'~': '`', '!': '1', '@': '2', '#': '3', '$': '4', '%': '5', '^': '6', '&': '7', '*': '8', '(': '9', ')': '0',
+	'_': '-', '+': '=', '{': '[', '}': ']', '|': '\\', ':': ';', '"': '\'', '<': ',', '>': '.', '?': '/',
+}
+
+type StrengthMatch struct {
+	Pattern string
+	Token   string
+	Start   int
+	End     int // exclusive
+	Guesses float64
+	Detail  string
+}
+
+type StrengthResult struct {
+	Password   string
+	Guesses    float64
+	Bits       float64
+	Score      int
+	Sequence   []StrengthMatch
+	CrackTimes []CrackTime
+	Feedback   []string
+}
+
+type CrackTime struct {
+	Scenario string
+	Seconds  float64
+}
+
+var listCrackScenarios = []struct {
+	Name         string
+	GuessPerSecs float64
+}{
+	{"Online, throttled (100/hour)", 100.0 / 3600},
+	{"Online, unthrottled (10/s)", 10},
+	{"Offline, slow hash (10k/s)", 1e4},
+	{"Offline, fast hash (10B/s)", 1e10},
+}
+
+func rankedDictionary(words []string) map[string]int {
+	ranked := make(map[string]int, len(words))
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if _, ok := ranked[word]; !ok {
+			ranked[word] = i + 1
+		}
+	}
+
+	return ranked
+}
+
+// charsetEntropy is the entropy in bits of a uniformly random string.
+func charsetEntropy(charsetSize, length int) float64 {
+	if charsetSize <= 1 || length <= 0 {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(charsetSize))
+}
+
+func bruteforceCardinality(s string) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < 128:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	cardinality := 0
+	if lower {
+		cardinality += 26
+	}
+	if upper {
+		cardinality += 26
+	}
+	if digit {
+		cardinality += 10
+	}
+	if symbol {
+		cardinality += 33
+	}
+	if other {
+		cardinality += 100
+	}
+
+	return cardinality
+}
+
+func uppercaseVariations(token string) float64 {
+	var upper, lower int
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+
+	// first letter, last letter or all caps are the first things tried
+	runes := []rune(token)
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1]))) {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+
+	return variations
+}
+
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result *= float64(n-k+i) / float64(i)
+	}
+
+	return result
+}
+
+func dictionaryMatches(password string) []StrengthMatch {
+	var matches []StrengthMatch
+	runes := []rune(password)
+	lower := []rune(strings.ToLower(password))
+
+	unleet := make([]rune, len(lower))
+	leeted := 0
+	for i, r := range lower {
+		if sub, ok := l33tTable[r]; ok {
+			unleet[i] = sub
+			leeted++
+		} else {
+			unleet[i] = r
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		for j := i + 3; j <= len(runes); j++ {
+			word := string(lower[i:j])
+			reversed := reverseString(word)
+			token := string(runes[i:j])
+
+			for name, dict := range strengthDictionaries {
+				if rank, ok := dict[word]; ok {
+					matches = append(matches, StrengthMatch{
+						Pattern: "dictionary", Token: token, Start: i, End: j,
+						Guesses: float64(rank) * uppercaseVariations(token),
+						Detail:  fmt.Sprintf("%s, rank %d", name, rank),
+					})
+				}
+
+				if rank, ok := dict[reversed]; ok && reversed != word {
+					matches = append(matches, StrengthMatch{
+						Pattern: "dictionary", Token: token, Start: i, End: j,
+						Guesses: float64(rank) * uppercaseVariations(token) * 2,
+						Detail:  fmt.Sprintf("reversed %s, rank %d", name, rank),
+					})
+				}
+
+				if leeted == 0 {
+					continue
+				}
+
+				plain := string(unleet[i:j])
+				if rank, ok := dict[plain]; ok && plain != word {
+					substitutions := 0
+					for k := i; k < j; k++ {
+						if unleet[k] != lower[k] {
+							substitutions++
+						}
+					}
+
+					matches = append(matches, StrengthMatch{
+						Pattern: "dictionary", Token: token, Start: i, End: j,
+						Guesses: float64(rank) * uppercaseVariations(token) * math.Pow(2, float64(substitutions)),
+						Detail:  fmt.Sprintf("l33t %s (%s), rank %d", name, plain, rank),
+					})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+func keyPosition(r rune) (x float64, y int, ok bool) {
+	r = unicode.ToLower(r)
+	if base, shifted := keyboardShifted[r]; shifted {
+		r = base
+	}
+
+	for row, keys := range keyboardRows {
+		if col := strings.IndexRune(keys, r); col >= 0 {
+			// each row is staggered half a key to the right of the one above
+			return float64(col) + float64(row)*0.5, row, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+func spatialMatches(password string) []StrengthMatch {
+	var matches []StrengthMatch
+	runes := []rune(password)
+
+	i := 0
+	for i < len(runes)-2 {
+		j := i + 1
+		turns := 0
+		lastDirection := ""
+
+		for j < len(runes) {
+			x1, y1, ok1 := keyPosition(runes[j-1])
+			x2, y2, ok2 := keyPosition(runes[j])
+			if !ok1 || !ok2 {
+				break
+			}
+
+			dx, dy := x2-x1, y2-y1
+			adjacent := (dy == 0 && math.Abs(dx) == 1) || (math.Abs(float64(dy)) == 1 && math.Abs(dx) <= 0.5)
+			if !adjacent {
+				break
+			}
+
+			direction := fmt.Sprintf("%.1f,%d", dx, dy)
+			if direction != lastDirection {
+				turns++
+				lastDirection = direction
+			}
+			j++
+		}
+
+		if j-i >= 3 {
+			token := string(runes[i:j])
+			guesses := 47 * math.Pow(4.6, float64(turns)) * float64(j-i)
+			matches = append(matches, StrengthMatch{
+				Pattern: "keyboard", Token: token, Start: i, End: j,
+				Guesses: guesses * uppercaseVariations(token),
+				Detail:  fmt.Sprintf("keyboard walk with %d turns", turns),
+			})
+			i = j - 1
+			continue
+		}
+		i++
+	}
+
+	return matches
+}
+
+func repeatMatches(password string) []StrengthMatch {
+	var matches []StrengthMatch
+	runes := []rune(password)
+
+	for i := 0; i < len(runes); i++ {
+		for size := 1; size <= (len(runes)-i)/2; size++ {
+			base := string(runes[i : i+size])
+			j := i + size
+			for j+size <= len(runes) && string(runes[j:j+size]) == base {
+				j += size
+			}
+
+			count := (j - i) / size
+			if count < 2 || (size == 1 && count < 3) {
+				continue
+			}
+
+			baseGuesses := math.Pow(float64(max(bruteforceCardinality(base), 10)), float64(size))
+			matches = append(matches, StrengthMatch{
+				Pattern: "repeat", Token: string(runes[i:j]), Start: i, End: j,
+				Guesses: baseGuesses * float64(count),
+				Detail:  fmt.Sprintf("%q repeated %d times", base, count),
+			})
+		}
+	}
+
+	return matches
+}
+
+func sequenceMatches(password string) []StrengthMatch {
+	var matches []StrengthMatch
+	runes := []rune(password)
+
+	i := 0
+	for i < len(runes)-2 {
+		delta := runes[i+1] - runes[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+
+		j := i + 2
+		for j < len(runes) && runes[j]-runes[j-1] == delta {
+			j++
+		}
+
+		if j-i >= 3 {
+			first := runes[i]
+			base := 26.0
+			switch {
+			case first == 'a' || first == 'A' || first == '0' || first == '1' || first == 'z' || first == '9':
+				base = 4
+			case unicode.IsDigit(first):
+				base = 10
+			}
+			if delta < 0 {
+				base *= 2
+			}
+
+			matches = append(matches, StrengthMatch{
+				Pattern: "sequence", Token: string(runes[i:j]), Start: i, End: j,
+				Guesses: base * float64(j-i),
+				Detail:  "alphabetical or numeric sequence",
+			})
+		}
+		i = j - 1
+	}
+
+	return matches
+}
+
+func yearMatches(password string) []StrengthMatch {
+	var matches []StrengthMatch
+	runes := []rune(password)
+
+	for i := 0; i+4 <= len(runes); i++ {
+		token := string(runes[i : i+4])
+		var year int
+		if _, err := fmt.Sscanf(token, "%4d", &year); err != nil || !unicode.IsDigit(runes[i]) {
+			continue
+		}
+
+		if year >= 1900 && year <= time.Now().Year()+20 {
+			matches = append(matches, StrengthMatch{
+				Pattern: "year", Token: token, Start: i, End: i + 4,
+				Guesses: float64(max(time.Now().Year()-year, 20)),
+				Detail:  "recent year",
+			})
+		}
+	}
+
+	return matches
+}
+
+// AnalyzePassword estimates how many guesses an informed attacker needs.
+func AnalyzePassword(password string) StrengthResult {
+	runes := []rune(password)
+	if len(runes) > strengthMaxLength {
+		runes = runes[:strengthMaxLength]
+		password = string(runes)
+	}
+
+	result := StrengthResult{Password: password}
+	if len(runes) == 0 {
+		result.Feedback = []string{"Password is empty"}
+		return result
+	}
+
+	var matches []StrengthMatch
+	matches = append(matches, dictionaryMatches(password)...)
+	matches = append(matches, spatialMatches(password)...)
+	matches = append(matches, repeatMatches(password)...)
+	matches = append(matches, sequenceMatches(password)...)
+	matches = append(matches, yearMatches(password)...)
+
+	cardinality := float64(bruteforceCardinality(password))
+
+	// best[i] is the fewest log10 guesses to cover the first i runes
+	n := len(runes)
+	best := make([]float64, n+1)
+	choice := make([]*StrengthMatch, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = best[i-1] + math.Log10(cardinality)
+		choice[i] = nil
+
+		for k := range matches {
+			m := &matches[k]
+			if m.End != i {
+				continue
+			}
+
+			if cost := best[m.Start] + math.Log10(math.Max(m.Guesses, 1)); cost < best[i] {
+				best[i] = cost
+				choice[i] = m
+			}
+		}
+	}
+
+	// walk back to collect the chosen patterns, merging brute force runs
+	var sequence []StrengthMatch
+	for i := n; i > 0; {
+		if m := choice[i]; m != nil {
+			sequence = append([]StrengthMatch{*m}, sequence...)
+			i = m.Start
+			continue
+		}
+
+		j := i
+		for j > 0 && choice[j] == nil {
+			j--
+		}
+		sequence = append([]StrengthMatch{{
+			Pattern: "bruteforce", Token: string(runes[j:i]), Start: j, End: i,
+			Guesses: math.Pow(cardinality, float64(i-j)),
+			Detail:  fmt.Sprintf("%d possible characters each", int(cardinality)),
+		}}, sequence...)
+		i = j
+	}
+
+	// an attacker also has to guess how many patterns were combined
+	log10Guesses := best[n] + math.Log10(factorial(len(sequence)))
+
+	result.Guesses = math.Pow(10, log10Guesses)
+	result.Bits = log10Guesses * math.Log2(10)
+	result.Sequence = sequence
+	result.Score = strengthScore(result.Guesses)
+
+	for _, scenario := range listCrackScenarios {
+		result.CrackTimes = append(result.CrackTimes, CrackTime{
+			Scenario: scenario.Name,
+			Seconds:  result.Guesses / 2 / scenario.GuessPerSecs,
+		})
+	}
+
+	result.Feedback = strengthFeedback(result)
+
+	return result
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+
+	return f
+}
+
+func strengthScore(guesses float64) int {
+	switch {
+	case guesses < 1e3:
+		return 0
+	case guesses < 1e6:
+		return 1
+	case guesses < 1e8:
+		return 2
+	case guesses < 1e10:
+		return 3
+	}
+
+	return 4
+}
+
+var listStrengthScore = []string{"Very weak", "Weak", "Fair", "Strong", "Very strong"}
+
+func strengthFeedback(result StrengthResult) []string {
+	var feedback []string
+	seen := map[string]bool{}
+
+	for _, m := range result.Sequence {
+		if seen[m.Pattern] {
+			continue
+		}
+		seen[m.Pattern] = true
+
+		switch m.Pattern {
+		case "dictionary":
+			feedback = append(feedback, "Avoid common passwords and dictionary words, even with l33t substitutions")
+		case "keyboard":
+			feedback = append(feedback, "Avoid keyboard walks like qwerty or asdf")
+		case "repeat":
+			feedback = append(feedback, "Avoid repeated characters and words")
+		case "sequence":
+			feedback = append(feedback, "Avoid sequences like abc or 1234")
+		case "year":
+			feedback = append(feedback, "Avoid years that are associated with you")
+		}
+	}
+
+	if result.Score < 3 && len([]rune(result.Password)) < 12 {
+		feedback = append(feedback, "Use a longer password, 12 characters or more")
+	}
+
+	return feedback
+}
+
+func formatCrackTime(seconds float64) string {
+	units := []struct {
+		Name    string
+		Seconds float64
+	}{
+		{"century", 100 * 365.25 * 24 * 3600},
+		{"year", 365.25 * 24 * 3600},
+		{"month", 30.44 * 24 * 3600},
+		{"day", 24 * 3600},
+		{"hour", 3600},
+		{"minute", 60},
+		{"second", 1},
+	}
+
+	if seconds < 1 {
+		return "less than a second"
+	}
+	if seconds >= 1e4*units[0].Seconds {
+		return "centuries"
+	}
+
+	for _, unit := range units {
+		if seconds >= unit.Seconds {
+			n := int(math.Round(seconds / unit.Seconds))
+			name := unit.Name
+			if n != 1 {
+				name += "s"
+				if unit.Name == "century" {
+					name = "centuries"
+				}
+			}
+
+			return fmt.Sprintf("%d %s", n, name)
+		}
+	}
+
+	return "less than a second"
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+)
+
+func showTool(w fyne.Window, open func()) func() {
+	return func() {
+		open()
+		w.Show()
+		w.RequestFocus()
+	}
+}
+
+func setupSystemTray(a fyne.App, w fyne.Window) {
+	desk, ok := a.(desktop.App)
+	if !ok {
+		return
+	}
+
+	statusItem := fyne.NewMenuItem(breakStatusText(), nil)
+	statusItem.Disabled = true
+
+	toggleItem := fyne.NewMenuItemWithIcon("Start Timer", theme.MediaPlayIcon(), func() {
+		timeBreak.Toggle()
+	})
+
+	toolsItem := fyne.NewMenuItem("Open Tool", nil)
+	toolsItem.ChildMenu = fyne.NewMenu("",
+		fyne.NewMenuItem("My Notes", showTool(w, windowNote(w))),
+		fyne.NewMenuItem("Time for Break", showTool(w, windowTimeBreak(w))),
+		fyne.NewMenuItem("Base64 Manager", showTool(w, windowBase64(w))),
+		fyne.NewMenuItem("String Generator", showTool(w, windowStringGenerator(w))),
+		fyne.NewMenuItem("Key Generator", showTool(w, windowKeyGenerator(w))),
+		fyne.NewMenuItem("QR Generator", showTool(w, windowQRGenerator(w))),
+		fyne.NewMenuItem("Env Viewer", showTool(w, windowEnvViewer(w))),
+	)
+
+	menu := fyne.NewMenu("Sesterdamp Apps",
+		statusItem,
+		toggleItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Show Window", showTool(w, func() {})),
+		toolsItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItemWithIcon("Quit", theme.LogoutIcon(), func() {
+			a.Quit()
+		}),
+	)
+	// fyne adds its own "Quit" item unless the menu already has one
+	menu.Items[len(menu.Items)-1].IsQuit = true
+
+	timeBreak.OnChange("tray", func() {
+		_, running, _ := timeBreak.Status()
+
+		statusItem.Label = breakStatusText()
+		if running {
+			toggleItem.Label = "Pause Timer"
+			toggleItem.Icon = theme.MediaPauseIcon()
+		} else {
+			toggleItem.Label = "Resume Timer"
+			toggleItem.Icon = theme.MediaPlayIcon()
+		}
+
+		menu.Refresh()
+	})
+
+	desk.SetSystemTrayIcon(theme.HomeIcon())
+	desk.SetSystemTrayMenu(menu)
+	desk.SetSystemTrayWindow(w)
+
+	// keep running in the tray when the main window is closed
+	w.SetCloseIntercept(func() {
+		onToolHide()
+		w.Hide()
+	})
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+)
+
+var listTemplateKind = []string{"Mask", "Regex"}
+
+// unbounded regex repeats (*, +, {n,}) are capped so the output stays readable
+const templateMaxRepeat = 8
+
+var templateMaskClasses = map[rune]string{
+	'#': "0123456789",
+	'?': "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	'*': "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	'@': "abcdefghijklmnopqrstuvwxyz",
+	'^': "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+}
+
+const templateMaskHelp = "# digit, ? letter, * letter or digit, @ lowercase, ^ uppercase, \\ escapes the next character"
+
+type TemplateError struct {
+	Pattern string
+	Offset  int
+	Message string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("invalid pattern at offset %d: %s\n%s\n%s^", e.Offset, e.Message, e.Pattern, strings.Repeat(" ", e.Offset))
+}
+
+type templatePart struct {
+	literal string
+	charset []rune
+}
+
+func compileMask(pattern string) ([]templatePart, error) {
+	var parts []templatePart
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\\' {
+			if i+1 >= len(runes) {
+				return nil, &TemplateError{Pattern: pattern, Offset: i, Message: "trailing '\\' has nothing to escape"}
+			}
+			i++
+			parts = append(parts, templatePart{literal: string(runes[i])})
+			continue
+		}
+
+		if charset, ok := templateMaskClasses[r]; ok {
+			parts = append(parts, templatePart{charset: []rune(charset)})
+			continue
+		}
+
+		parts = append(parts, templatePart{literal: string(r)})
+	}
+
+	return parts, nil
+}
+
+func generateMask(parts []templatePart) (string, error) {
+	var sb strings.Builder
+	for _, part := range parts {
+		if part.charset == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		n, err := randomInt(len(part.charset))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteRune(part.charset[n])
+	}
+
+	return sb.String(), nil
+}
+
+func regexError(pattern string, err error) error {
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	offset := strings.Index(pattern, syntaxErr.Expr)
+	if offset < 0 {
+		offset = 0
+	}
+
+	return &TemplateError{Pattern: pattern, Offset: len([]rune(pattern[:offset])), Message: fmt.Sprintf("%s: `%s`", syntaxErr.Code, syntaxErr.Expr)}
+}
+
+func compileRegex(pattern string) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, regexError(pattern, err)
+	}
+
+	return re.Simplify(), nil
+}
+
+// printableClass narrows a rune class to printable ASCII, so negated classes and
+// '.' don't produce control or exotic unicode characters.
+func printableClass(ranges []rune) []rune {
+	var out []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], 0x20), min(ranges[i+1], 0x7e)
+		if lo <= hi {
+			out = append(out, lo, hi)
+		}
+	}
+
+	if out == nil {
+		return ranges
+	}
+
+	return out
+}
+
+func randomFromClass(ranges []rune) (rune, error) {
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return 0, errors.New("character class matches nothing")
+	}
+
+	n, err := randomInt(total)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n), nil
+		}
+		n -= size
+	}
+
+	return ranges[0], nil
+}
+
+func generateRegex(re *syntax.Regexp, sb *strings.Builder) error {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return nil
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			// case-insensitive literals pick a random case
+			if re.Flags&syntax.FoldCase != 0 && strings.ToUpper(string(r)) != strings.ToLower(string(r)) {
+				upper, err := randomInt(2)
+				if err != nil {
+					return err
+				}
+				if upper == 1 {
+					sb.WriteString(strings.ToUpper(string(r)))
+				} else {
+					sb.WriteString(strings.ToLower(string(r)))
+				}
+				continue
+			}
+			sb.WriteRune(r)
+		}
+		return nil
+	case syntax.OpCharClass:
+		r, err := randomFromClass(printableClass(re.Rune))
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+		return nil
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		r, err := randomFromClass([]rune{0x20, 0x7e})
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+		return nil
+	case syntax.OpCapture:
+		return generateRegex(re.Sub[0], sb)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := generateRegex(sub, sb); err != nil {
+				return err
+			}
+		}
+		return nil
+	case syntax.OpAlternate:
+		n, err := randomInt(len(re.Sub))
+		if err != nil {
+			return err
+		}
+		return generateRegex(re.Sub[n], sb)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, templateMaxRepeat
+		switch re.Op {
+		case syntax.OpPlus:
+			lo = 1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo = re.Min
+			hi = re.Max
+			if hi < 0 {
+				hi = max(lo, templateMaxRepeat)
+			}
+		}
+
+		n, err := randomInt(hi - lo + 1)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < lo+n; i++ {
+			if err := generateRegex(re.Sub[0], sb); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("regex operator %v is not supported", re.Op)
+}
+
+// compileTemplate validates pattern once and returns a generator for it.
+func compileTemplate(kind string, pattern string) (func() (string, error), error) {
+	if pattern == "" {
+		return nil, errors.New("pattern must be fill")
+	}
+
+	if kind == "Regex" {
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (string, error) {
+			var sb strings.Builder
+			err := generateRegex(re, &sb)
+			return sb.String(), err
+		}, nil
+	}
+
+	parts, err := compileMask(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (string, error) {
+		return generateMask(parts)
+	}, nil
+}
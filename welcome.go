@@ -36,7 +36,7 @@ func padding(v float32, obj fyne.CanvasObject) *fyne.Container {
 	)
 }
 
-func NewWelcomePanel(windowNote func(), windowTimeBreak func(), windowBase64 func(), windowStringGenerator func(), windowQRGenerator func(), windowEnvViewer func()) fyne.CanvasObject {
+func NewWelcomePanel(windowNote func(), windowTimeBreak func(), windowBase64 func(), windowStringGenerator func(), windowQRGenerator func(), windowEnvViewer func(), windowKeyGenerator func()) fyne.CanvasObject {
 	img := canvas.NewImageFromResource(theme.HomeIcon())
 	img.FillMode = canvas.ImageFillContain
 	img.SetMinSize(fyne.NewSize(100, 100))
@@ -114,6 +114,16 @@ func NewWelcomePanel(windowNote func(), windowTimeBreak func(), windowBase64 fun
 		}),
 	)
 
+	cardKeyGenerator := widget.NewCard(
+		"Key Generator",
+		"Buat SSH key dan sertifikat TLS untuk development",
+		widget.NewButton("Open", func() {
+			if windowKeyGenerator != nil {
+				windowKeyGenerator()
+			}
+		}),
+	)
+
 	grid := container.NewGridWithColumns(3,
 		cardNotes,
 		cardTimeBreak,
@@ -121,6 +131,7 @@ func NewWelcomePanel(windowNote func(), windowTimeBreak func(), windowBase64 fun
 		cardString,
 		cardQRGenerator,
 		cardEnvViewer,
+		cardKeyGenerator,
 	)
 
 	panel := container.NewVBox(
@@ -142,38 +153,36 @@ func welcomes(w fyne.Window) fyne.CanvasObject {
 		windowStringGenerator(w),
 		windowQRGenerator(w),
 		windowEnvViewer(w),
+		windowKeyGenerator(w),
 	)
 
 	return welcome
 }
 
-func windowTimeBreak(w fyne.Window) func() {
-	return func() {
-		w.SetContent(
-			container.NewBorder(toolbars(w), nil, nil, nil, widget.NewLabel("Time Break Reminder")),
-		)
-	}
-}
-
-func windowBase64(w fyne.Window) func() {
-	return func() {
-		w.SetContent(
-			container.NewBorder(toolbars(w), nil, nil, nil, widget.NewLabel("Base64 Manager")),
-		)
-	}
-}
-
 var emptyFunc = func() {
 	fmt.Println("click inisiate func!")
 }
 
 var notePreviewToolbar *widget.ToolbarAction
 
+// onToolLeave and onToolHide are set by the open tool, after its content is
+// shown, to release what it holds when it is replaced or the window hides.
+var onToolLeave = func() {}
+var onToolHide = func() {}
+
+// setToolContent replaces the window content, letting the previous tool clean up first.
+func setToolContent(w fyne.Window, content fyne.CanvasObject) {
+	onToolLeave()
+	onToolLeave, onToolHide = func() {}, func() {}
+
+	w.SetContent(content)
+}
+
 func toolbars(w fyne.Window) *widget.Toolbar {
 	toolbar := widget.NewToolbar(
 		widget.NewToolbarAction(theme.HomeIcon(), func() {
 			fmt.Println("Home clicked")
-			w.SetContent(welcomes(w))
+			setToolContent(w, welcomes(w))
 		}),
 		notePreviewToolbar,
 		widget.NewToolbarAction(theme.SettingsIcon(), func() {
@@ -192,7 +201,16 @@ func main() {
 
 	notePreviewToolbar = widget.NewToolbarAction(theme.VisibilityOffIcon(), emptyFunc)
 
-	w.SetContent(welcomes(w))
+	setToolContent(w, welcomes(w))
 	w.Resize(fyne.NewSize(1200, 800))
+
+	onBreakStart = func() {
+		a.SendNotification(fyne.NewNotification("Time for Break", "Take a rest from the screen for a while."))
+		startBreakExercises(w)
+	}
+
+	setupSystemTray(a, w)
+	go timeBreak.Run()
+
 	w.ShowAndRun()
 }
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var listBase64Alphabet = []string{"Standard", "URL-safe"}
+
+func base64Encoding(alphabet string, padded bool) *base64.Encoding {
+	enc := base64.StdEncoding
+	if alphabet == "URL-safe" {
+		enc = base64.URLEncoding
+	}
+
+	if !padded {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+
+	return enc
+}
+
+func base64Alphabet(alphabet string) string {
+	chars := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	if alphabet == "URL-safe" {
+		return chars + "-_"
+	}
+
+	return chars + "+/"
+}
+
+func encodeBase64(data []byte, alphabet string, padded bool) string {
+	return base64Encoding(alphabet, padded).EncodeToString(data)
+}
+
+func decodeBase64(text string, alphabet string, padded bool) ([]byte, error) {
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+	leading := len(text) - len(trimmed)
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+
+	data, err := base64Encoding(alphabet, padded).DecodeString(trimmed)
+
+	var corrupt base64.CorruptInputError
+	if errors.As(err, &corrupt) {
+		offset := int(corrupt) + leading
+		name := strings.ToLower(alphabet)
+
+		// a valid character at the offset means the last quantum is incomplete
+		if offset < len(text) {
+			r, size := utf8.DecodeRuneInString(text[offset:])
+			if !strings.ContainsRune(base64Alphabet(alphabet), r) {
+				unexpected := fmt.Sprintf("%q", r)
+				if r == utf8.RuneError && size == 1 {
+					unexpected = fmt.Sprintf("byte %#x", text[offset])
+				}
+				return nil, fmt.Errorf("invalid %s base64 at byte offset %d: unexpected %s", name, offset, unexpected)
+			}
+		}
+
+		return nil, fmt.Errorf("invalid %s base64 near byte offset %d: input is truncated or padding is wrong", name, offset)
+	}
+
+	return data, err
+}
+
+// base64TextTab converts text and files, toChain hands the output to the chain tab.
+func base64TextTab(w fyne.Window, toChain func([]byte)) fyne.CanvasObject {
+	inputArea := widget.NewMultiLineEntry()
+	inputArea.SetPlaceHolder("Text or base64 to convert")
+	inputArea.Wrapping = fyne.TextWrapBreak
+
+	outputArea := widget.NewMultiLineEntry()
+	outputArea.SetPlaceHolder("Result will appear here")
+	outputArea.Wrapping = fyne.TextWrapBreak
+
+	alphabetOptions := widget.NewSelect(listBase64Alphabet, nil)
+	alphabetOptions.SetSelected("Standard")
+
+	paddedCheck := widget.NewCheck("Padding (=)", nil)
+	paddedCheck.SetChecked(true)
+
+	statusLabel := widget.NewLabel("")
+
+	// binary data is shown as a hex dump, the bytes themselves are kept here
+	// until the area is edited
+	var inputData, outputData []byte
+	inputArea.OnChanged = func(string) { inputData = nil }
+	outputArea.OnChanged = func(string) { outputData = nil }
+
+	inputBytes := func() []byte {
+		if inputData != nil {
+			return inputData
+		}
+		return []byte(inputArea.Text)
+	}
+	outputBytes := func() []byte {
+		if outputData != nil {
+			return outputData
+		}
+		return []byte(outputArea.Text)
+	}
+
+	encode := func() {
+		data := inputBytes()
+		outputArea.SetText(encodeBase64(data, alphabetOptions.Selected, paddedCheck.Checked))
+		statusLabel.SetText(fmt.Sprintf("Encoded %d bytes", len(data)))
+	}
+
+	previewStack := container.NewStack(widget.NewLabel("Decode something to preview it here"))
+	outputTabs := container.NewAppTabs(
+		container.NewTabItemWithIcon("Text", theme.DocumentIcon(), outputArea),
+		container.NewTabItemWithIcon("Preview", theme.VisibilityIcon(), previewStack),
+	)
+
+	var showDecoded func(data []byte)
+	showDecoded = func(data []byte) {
+		if utf8.Valid(data) {
+			outputArea.SetText(string(data))
+		} else {
+			outputArea.SetText(hexDump(data))
+			outputData = data
+		}
+
+		previewStack.Objects = []fyne.CanvasObject{decodedPreview(w, data, showDecoded)}
+		previewStack.Refresh()
+
+		if kind := sniffBytes(data).Kind; kind != "text" && kind != "empty" {
+			outputTabs.SelectIndex(1)
+		} else {
+			outputTabs.SelectIndex(0)
+		}
+
+		statusLabel.SetText(fmt.Sprintf("Decoded %d bytes", len(data)))
+	}
+
+	decode := func() {
+		data, err := decodeBase64(string(inputBytes()), alphabetOptions.Selected, paddedCheck.Checked)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		showDecoded(data)
+	}
+
+	encodeBtn := widget.NewButtonWithIcon("Encode", theme.MoveDownIcon(), encode)
+	decodeBtn := widget.NewButtonWithIcon("Decode", theme.MoveUpIcon(), decode)
+
+	swapBtn := widget.NewButtonWithIcon("Swap", theme.ViewRefreshIcon(), func() {
+		inputText, input := inputArea.Text, inputData
+		outputText, output := outputArea.Text, outputData
+
+		inputArea.SetText(outputText)
+		inputData = output
+		outputArea.SetText(inputText)
+		outputData = input
+	})
+
+	clearBtn := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
+		inputArea.SetText("")
+		outputArea.SetText("")
+		statusLabel.SetText("")
+
+		previewStack.Objects = []fyne.CanvasObject{widget.NewLabel("Decode something to preview it here")}
+		previewStack.Refresh()
+	})
+
+	copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(clipboardBytes(outputBytes()))
+	})
+
+	chainBtn := widget.NewButtonWithIcon("To Chain", theme.ListIcon(), func() {
+		toChain(outputBytes())
+	})
+
+	encodeFileBtn := widget.NewButtonWithIcon("Encode File", theme.FileIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			outputArea.SetText(encodeBase64(data, alphabetOptions.Selected, paddedCheck.Checked))
+			statusLabel.SetText(fmt.Sprintf("Encoded %s (%d bytes)", reader.URI().Name(), len(data)))
+		}, w)
+	})
+
+	decodeFileBtn := widget.NewButtonWithIcon("Decode to File", theme.DocumentSaveIcon(), func() {
+		data, err := decodeBase64(inputArea.Text, alphabetOptions.Selected, paddedCheck.Checked)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if _, err := writer.Write(data); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			statusLabel.SetText(fmt.Sprintf("Saved %d bytes to %s", len(data), writer.URI().Name()))
+		}, w)
+	})
+
+	// shortcuts: Ctrl+E for encode, Ctrl+D for decode
+	if c := w.Canvas(); c != nil {
+		c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyE, Modifier: fyne.KeyModifierControl}, func(fyne.Shortcut) {
+			encode()
+		})
+		c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyD, Modifier: fyne.KeyModifierControl}, func(fyne.Shortcut) {
+			decode()
+		})
+	}
+
+	options := container.NewHBox(
+		widget.NewLabel("Alphabet:"), alphabetOptions,
+		paddedCheck,
+		widget.NewSeparator(),
+		encodeBtn, decodeBtn, swapBtn, clearBtn,
+		widget.NewSeparator(),
+		encodeFileBtn, decodeFileBtn,
+	)
+
+	inputLabel := widget.NewLabelWithStyle("Input", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	input := container.NewBorder(inputLabel, nil, nil, nil, inputArea)
+
+	outputLabel := widget.NewLabelWithStyle("Output", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	output := container.NewBorder(container.NewBorder(nil, nil, outputLabel, container.NewHBox(copyBtn, chainBtn)), nil, nil, nil, outputTabs)
+
+	split := container.NewHSplit(padding(10, input), padding(10, output))
+	split.SetOffset(0.5)
+
+	return container.NewBorder(
+		container.NewVBox(padding(5, options), widget.NewSeparator()),
+		padding(5, statusLabel), nil, nil,
+		split,
+	)
+}
+
+func windowBase64(w fyne.Window) func() {
+	return func() {
+		notePreviewToolbar.ToolbarObject().Hide()
+
+		var tabs *container.AppTabs
+		chainTab, setChainInput := base64ChainTab(w)
+		textTab := base64TextTab(w, func(data []byte) {
+			setChainInput(data)
+			tabs.SelectIndex(2)
+		})
+
+		tabs = container.NewAppTabs(
+			container.NewTabItemWithIcon("Text", theme.DocumentIcon(), textTab),
+			container.NewTabItemWithIcon("File Stream", theme.StorageIcon(), base64StreamTab(w)),
+			container.NewTabItemWithIcon("Chain", theme.ListIcon(), chainTab),
+			container.NewTabItemWithIcon("JWT", theme.AccountIcon(), base64JWTTab(w)),
+			container.NewTabItemWithIcon("Data URI", theme.FileImageIcon(), base64DataURITab(w)),
+		)
+
+		setToolContent(w,
+			container.NewBorder(toolbars(w), nil, nil, nil, tabs),
+		)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func displayBytes(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	return hexDump(data)
+}
+
+func chainResultCard(w fyne.Window, title string, data []byte) fyne.CanvasObject {
+	value := widget.NewLabel(displayBytes(data))
+	value.Wrapping = fyne.TextWrapBreak
+	if !utf8.Valid(data) {
+		value.TextStyle = fyne.TextStyle{Monospace: true}
+	}
+
+	copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(clipboardBytes(data))
+	})
+
+	label := widget.NewLabelWithStyle(fmt.Sprintf("%s • %d bytes", title, len(data)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, copyBtn, label),
+		value,
+		widget.NewSeparator(),
+	)
+}
+
+// base64ChainTab returns the chain and a setter for its input, which takes
+// binary data too: a gzip file can go through gunzip first.
+func base64ChainTab(w fyne.Window) (fyne.CanvasObject, func([]byte)) {
+	var steps []CodecStep
+	// inputData is the binary input the input area only shows as a hex dump
+	var inputData []byte
+
+	inputArea := widget.NewMultiLineEntry()
+	inputArea.SetPlaceHolder("Input for the first step")
+	inputArea.Wrapping = fyne.TextWrapBreak
+
+	codecOptions := widget.NewSelect(codecNames(), nil)
+	codecOptions.SetSelected(listCodecs[0].Name)
+
+	directionOptions := widget.NewRadioGroup([]string{"Encode", "Decode"}, nil)
+	directionOptions.Horizontal = true
+	directionOptions.SetSelected("Decode")
+
+	results := container.NewVBox()
+
+	run := func() {
+		results.RemoveAll()
+
+		input := inputData
+		if input == nil {
+			input = []byte(inputArea.Text)
+		}
+
+		outputs, err := runCodecChain(input, steps)
+		for i, out := range outputs {
+			results.Add(chainResultCard(w, fmt.Sprintf("%d. %s", i+1, steps[i]), out))
+		}
+
+		if err != nil {
+			errText := widget.NewRichText(&widget.TextSegment{
+				Text:  err.Error(),
+				Style: widget.RichTextStyle{ColorName: theme.ColorNameError},
+			})
+			errText.Wrapping = fyne.TextWrapWord
+			results.Add(errText)
+		}
+
+		if len(steps) == 0 {
+			results.Add(widget.NewLabel("Add a step to start the chain"))
+		}
+	}
+
+	var stepList *widget.List
+	stepList = widget.NewList(
+		func() int {
+			return len(steps)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil),
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				),
+				widget.NewLabel(""),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%d. %s", i+1, steps[i]))
+
+			buttons := row.Objects[1].(*fyne.Container)
+			buttons.Objects[0].(*widget.Button).OnTapped = func() {
+				if i > 0 {
+					steps[i-1], steps[i] = steps[i], steps[i-1]
+					stepList.Refresh()
+					run()
+				}
+			}
+			buttons.Objects[1].(*widget.Button).OnTapped = func() {
+				steps = append(steps[:i], steps[i+1:]...)
+				stepList.Refresh()
+				run()
+			}
+		},
+	)
+
+	addBtn := widget.NewButtonWithIcon("Add Step", theme.ContentAddIcon(), func() {
+		steps = append(steps, CodecStep{
+			Codec:  codecOptions.Selected,
+			Decode: directionOptions.Selected == "Decode",
+		})
+		stepList.Refresh()
+		run()
+	})
+
+	clearBtn := widget.NewButtonWithIcon("Clear Steps", theme.ContentClearIcon(), func() {
+		steps = nil
+		stepList.Refresh()
+		run()
+	})
+
+	inputArea.OnChanged = func(string) {
+		inputData = nil
+		run()
+	}
+	run()
+
+	setInput := func(data []byte) {
+		if utf8.Valid(data) {
+			inputArea.SetText(string(data))
+			return
+		}
+
+		inputArea.SetText(hexDump(data))
+		inputData = data
+		run()
+	}
+
+	loadBtn := widget.NewButtonWithIcon("Load File", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			setInput(data)
+		}, w)
+	})
+
+	builder := container.NewVBox(
+		widget.NewLabelWithStyle("Steps", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, directionOptions, codecOptions),
+		container.NewHBox(addBtn, clearBtn),
+	)
+
+	inputLabel := widget.NewLabelWithStyle("Input", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	left := container.NewVSplit(
+		container.NewBorder(container.NewBorder(nil, nil, inputLabel, loadBtn), nil, nil, nil, inputArea),
+		container.NewBorder(builder, nil, nil, nil, stepList),
+	)
+
+	resultLabel := widget.NewLabelWithStyle("Intermediate Results", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	right := container.NewBorder(resultLabel, nil, nil, nil, container.NewVScroll(results))
+
+	split := container.NewHSplit(padding(10, left), padding(10, right))
+	split.SetOffset(0.4)
+
+	return split, setInput
+}
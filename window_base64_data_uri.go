@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dataURIMIME prefers the sniffed type and falls back to the file extension for
+// types the sniffer can't tell apart from plain text, like CSS.
+func dataURIMIME(data []byte, fileName string) string {
+	sniff := sniffBytes(data)
+	if sniff.Kind == "image" || strings.HasPrefix(sniff.MIME, "font/") {
+		return sniff.MIME
+	}
+
+	if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))); byExt != "" {
+		// data URIs can't hold the space mime puts after ';'
+		return strings.ReplaceAll(byExt, " ", "")
+	}
+
+	if sniff.Kind == "json" {
+		return sniff.MIME
+	}
+
+	return strings.SplitN(sniff.MIME, ";", 2)[0]
+}
+
+func buildDataURI(data []byte, mimeType string) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func parseDataURI(uri string) (mimeType string, data []byte, err error) {
+	uri = strings.TrimSpace(uri)
+
+	// accept CSS url(...) wrappers as pasted from a stylesheet
+	if strings.HasPrefix(uri, "url(") && strings.HasSuffix(uri, ")") {
+		uri = strings.Trim(uri[4:len(uri)-1], `"' `)
+	}
+
+	if !strings.HasPrefix(strings.ToLower(uri), "data:") {
+		return "", nil, errors.New("data URI must start with \"data:\"")
+	}
+
+	comma := strings.IndexByte(uri, ',')
+	if comma < 0 {
+		return "", nil, errors.New("data URI has no ',' separating the header and data")
+	}
+
+	header := uri[len("data:"):comma]
+	payload := uri[comma+1:]
+
+	isBase64 := false
+	params := strings.Split(header, ";")
+	if strings.EqualFold(params[len(params)-1], "base64") {
+		isBase64 = true
+		params = params[:len(params)-1]
+	}
+
+	mimeType = strings.Join(params, ";")
+	if mimeType == "" {
+		mimeType = "text/plain;charset=US-ASCII"
+	}
+
+	if isBase64 {
+		// base64 may itself be percent-encoded when taken from a URL
+		if strings.Contains(payload, "%") {
+			if unescaped, err := url.PathUnescape(payload); err == nil {
+				payload = unescaped
+			}
+		}
+
+		payload = strings.TrimSpace(payload)
+		data, err = decodeBase64(payload, "Standard", len(payload)%4 == 0)
+		if err != nil {
+			return "", nil, fmt.Errorf("data URI payload: %w", err)
+		}
+
+		return mimeType, data, nil
+	}
+
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("data URI payload: %w", err)
+	}
+
+	return mimeType, []byte(text), nil
+}
+
+func base64DataURITab(w fyne.Window) fyne.CanvasObject {
+	var data []byte
+	var fileName string
+
+	uriArea := widget.NewMultiLineEntry()
+	uriArea.SetPlaceHolder("data:image/png;base64,iVBORw0KGgo...")
+	uriArea.Wrapping = fyne.TextWrapBreak
+
+	mimeEntry := widget.NewEntry()
+	mimeEntry.SetPlaceHolder("MIME type")
+
+	infoLabel := widget.NewLabel("")
+	previewStack := container.NewStack(widget.NewLabel("Choose a file or paste a data URI to preview it here"))
+
+	showPreview := func() {
+		previewStack.Objects = []fyne.CanvasObject{decodedPreview(w, data, nil)}
+		previewStack.Refresh()
+	}
+
+	build := func() {
+		if data == nil {
+			return
+		}
+
+		uriArea.SetText(buildDataURI(data, mimeEntry.Text))
+		infoLabel.SetText(fmt.Sprintf("%s • %d bytes → %d chars", fileName, len(data), len(uriArea.Text)))
+	}
+
+	chooseBtn := widget.NewButtonWithIcon("Choose File", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			data = content
+			fileName = reader.URI().Name()
+			mimeEntry.SetText(dataURIMIME(data, fileName))
+
+			build()
+			showPreview()
+		}, w)
+	})
+
+	rebuildBtn := widget.NewButtonWithIcon("Rebuild", theme.ViewRefreshIcon(), build)
+
+	parseBtn := widget.NewButtonWithIcon("Parse", theme.MoveDownIcon(), func() {
+		mimeType, content, err := parseDataURI(uriArea.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		data = content
+		fileName = "data"
+		mimeEntry.SetText(mimeType)
+		infoLabel.SetText(fmt.Sprintf("%s • %d bytes", mimeType, len(data)))
+
+		showPreview()
+	})
+
+	saveBtn := widget.NewButtonWithIcon("Save File", theme.DocumentSaveIcon(), func() {
+		if data == nil {
+			dialog.ShowError(errors.New("nothing to save, parse a data URI first"), w)
+			return
+		}
+
+		ext := mimeExtension(strings.SplitN(mimeEntry.Text, ";", 2)[0])
+		if ext == ".bin" {
+			ext = sniffBytes(data).Ext
+		}
+
+		name := strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ext
+		saveBytesAs(w, data, name, func(saved string) {
+			infoLabel.SetText(fmt.Sprintf("Saved %d bytes to %s", len(data), saved))
+		})
+	})
+
+	copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(uriArea.Text)
+	})
+
+	copyCSSBtn := widget.NewButtonWithIcon("Copy as CSS url()", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(fmt.Sprintf("url(\"%s\")", uriArea.Text))
+	})
+
+	actions := container.NewHBox(chooseBtn, rebuildBtn, parseBtn, saveBtn, widget.NewSeparator(), copyBtn, copyCSSBtn)
+
+	left := container.NewBorder(
+		container.NewVBox(actions, container.NewBorder(nil, nil, widget.NewLabel("MIME:"), nil, mimeEntry)),
+		infoLabel, nil, nil,
+		uriArea,
+	)
+
+	previewLabel := widget.NewLabelWithStyle("Preview", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	right := container.NewBorder(previewLabel, nil, nil, nil, previewStack)
+
+	split := container.NewHSplit(padding(10, left), padding(10, right))
+	split.SetOffset(0.5)
+
+	return split
+}
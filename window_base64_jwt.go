@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func statusText(text string, colorName fyne.ThemeColorName) *widget.RichText {
+	rt := widget.NewRichText(&widget.TextSegment{
+		Text:  text,
+		Style: widget.RichTextStyle{ColorName: colorName, TextStyle: fyne.TextStyle{Bold: true}},
+	})
+	rt.Wrapping = fyne.TextWrapWord
+
+	return rt
+}
+
+func base64JWTTab(w fyne.Window) fyne.CanvasObject {
+	tokenArea := widget.NewMultiLineEntry()
+	tokenArea.SetPlaceHolder("Paste a JWT (eyJhbGciOi...)")
+	tokenArea.Wrapping = fyne.TextWrapBreak
+
+	keyArea := widget.NewMultiLineEntry()
+	keyArea.SetPlaceHolder("HMAC secret, PEM public key / certificate, or JWK / JWK set")
+	keyArea.Wrapping = fyne.TextWrapBreak
+
+	secretBase64Check := widget.NewCheck("HMAC secret is base64", nil)
+
+	headerStack := container.NewStack()
+	payloadStack := container.NewStack()
+	claimsLabel := widget.NewLabel("")
+	claimsLabel.Wrapping = fyne.TextWrapWord
+	verifyStack := container.NewStack()
+
+	var current *JWT
+
+	verify := func() {
+		if current == nil {
+			verifyStack.Objects = nil
+			verifyStack.Refresh()
+			return
+		}
+
+		var result *widget.RichText
+		if strings.TrimSpace(keyArea.Text) == "" {
+			result = statusText("Signature not verified, paste a secret or public key", theme.ColorNameDisabled)
+		} else if err := VerifyJWT(current, keyArea.Text, secretBase64Check.Checked); err != nil {
+			result = statusText("✘ "+current.Alg()+": "+err.Error(), theme.ColorNameError)
+		} else {
+			result = statusText("✔ "+current.Alg()+" signature verified", theme.ColorNameSuccess)
+		}
+
+		verifyStack.Objects = []fyne.CanvasObject{result}
+		verifyStack.Refresh()
+	}
+
+	decode := func() {
+		current = nil
+
+		if strings.TrimSpace(tokenArea.Text) == "" {
+			headerStack.Objects = nil
+			payloadStack.Objects = nil
+			claimsLabel.SetText("")
+		} else if token, err := parseJWT(tokenArea.Text); err != nil {
+			headerStack.Objects = []fyne.CanvasObject{statusText(err.Error(), theme.ColorNameError)}
+			payloadStack.Objects = nil
+			claimsLabel.SetText("")
+		} else {
+			current = token
+			headerStack.Objects = []fyne.CanvasObject{jsonToRichText(string(token.HeaderJSON))}
+			payloadStack.Objects = []fyne.CanvasObject{jsonToRichText(string(token.PayloadJSON))}
+			claimsLabel.SetText(strings.Join(token.TimeClaims(time.Now()), "\n"))
+		}
+
+		headerStack.Refresh()
+		payloadStack.Refresh()
+		verify()
+	}
+
+	tokenArea.OnChanged = func(string) { decode() }
+	keyArea.OnChanged = func(string) { verify() }
+	secretBase64Check.OnChanged = func(bool) { verify() }
+
+	copyPayloadBtn := widget.NewButtonWithIcon("Copy Payload", theme.ContentCopyIcon(), func() {
+		if current != nil {
+			w.Clipboard().SetContent(string(current.PayloadJSON))
+		}
+	})
+
+	bold := func(text string) *widget.Label {
+		return widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+
+	left := container.NewVSplit(
+		container.NewBorder(bold("Token"), nil, nil, nil, tokenArea),
+		container.NewBorder(container.NewBorder(nil, nil, bold("Verify Key"), secretBase64Check), nil, nil, nil, keyArea),
+	)
+
+	decoded := container.NewVBox(
+		bold("Header"),
+		headerStack,
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, bold("Payload"), copyPayloadBtn),
+		payloadStack,
+		widget.NewSeparator(),
+		bold("Time Claims"),
+		claimsLabel,
+		widget.NewSeparator(),
+		bold("Signature"),
+		verifyStack,
+	)
+
+	split := container.NewHSplit(padding(10, left), padding(10, container.NewVScroll(decoded)))
+	split.SetOffset(0.4)
+
+	return split
+}
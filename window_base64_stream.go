@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const base64StreamBufferSize = 256 * 1024
+
+// progressReader counts the bytes read and stops early when ctx is cancelled.
+type progressReader struct {
+	ctx    context.Context
+	reader io.Reader
+	read   atomic.Int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	r.read.Add(int64(n))
+
+	return n, err
+}
+
+// streamBase64 encodes or decodes src into dst in fixed size chunks, so the file
+// never has to fit in memory.
+func streamBase64(dst io.Writer, src io.Reader, decode bool, alphabet string, padded bool) error {
+	enc := base64Encoding(alphabet, padded)
+	buf := make([]byte, base64StreamBufferSize)
+
+	if decode {
+		// the decoder reads ahead, so only the range of the bad byte is known
+		counter := &progressReader{ctx: context.Background(), reader: src}
+		_, err := io.CopyBuffer(dst, base64.NewDecoder(enc, counter), buf)
+
+		var corrupt base64.CorruptInputError
+		if errors.As(err, &corrupt) {
+			return fmt.Errorf("invalid %s base64 within the first %d bytes of the input file", strings.ToLower(alphabet), counter.read.Load())
+		}
+
+		return err
+	}
+
+	encoder := base64.NewEncoder(enc, dst)
+	if _, err := io.CopyBuffer(encoder, src, buf); err != nil {
+		return err
+	}
+
+	// flush the last partial quantum and padding
+	return encoder.Close()
+}
+
+func uriSize(uri fyne.URI) int64 {
+	if uri == nil || uri.Scheme() != "file" {
+		return 0
+	}
+
+	info, err := os.Stat(uri.Path())
+	if err != nil {
+		return 0
+	}
+
+	return info.Size()
+}
+
+func base64StreamTab(w fyne.Window) fyne.CanvasObject {
+	var inputURI fyne.URI
+	var outputDir fyne.ListableURI
+	var cancel context.CancelFunc
+
+	inputLabel := widget.NewLabel("No input file selected")
+	inputLabel.Truncation = fyne.TextTruncateEllipsis
+	outputLabel := widget.NewLabel("No output folder selected")
+	outputLabel.Truncation = fyne.TextTruncateEllipsis
+	outputNameEntry := widget.NewEntry()
+	outputNameEntry.SetPlaceHolder("Output file name")
+
+	modeOptions := widget.NewRadioGroup([]string{"Encode", "Decode"}, nil)
+	modeOptions.Horizontal = true
+	modeOptions.SetSelected("Encode")
+
+	alphabetOptions := widget.NewSelect(listBase64Alphabet, nil)
+	alphabetOptions.SetSelected("Standard")
+
+	paddedCheck := widget.NewCheck("Padding (=)", nil)
+	paddedCheck.SetChecked(true)
+
+	progress := widget.NewProgressBar()
+	statusLabel := widget.NewLabel("")
+
+	inputBtn := widget.NewButtonWithIcon("Choose Input", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+
+			inputURI = reader.URI()
+			inputLabel.SetText(inputURI.Path())
+		}, w)
+	})
+
+	// a save dialog would create the file on pick, the output is only opened on Start
+	outputBtn := widget.NewButtonWithIcon("Choose Folder", theme.FolderOpenIcon(), func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if dir == nil {
+				return
+			}
+
+			outputDir = dir
+			outputLabel.SetText(outputDir.Path())
+		}, w)
+	})
+
+	startBtn := widget.NewButtonWithIcon("Start", theme.MediaPlayIcon(), nil)
+	cancelBtn := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	cancelBtn.Disable()
+
+	start := func(outputURI fyne.URI) {
+		reader, err := storage.Reader(inputURI)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		writer, err := storage.Writer(outputURI)
+		if err != nil {
+			reader.Close()
+			dialog.ShowError(err, w)
+			return
+		}
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancel = cancelFunc
+
+		src := &progressReader{ctx: ctx, reader: reader}
+		total := uriSize(inputURI)
+		decode := modeOptions.Selected == "Decode"
+		alphabet := alphabetOptions.Selected
+		padded := paddedCheck.Checked
+
+		progress.SetValue(0)
+		statusLabel.SetText("Processing...")
+		startBtn.Disable()
+		cancelBtn.Enable()
+
+		done := make(chan struct{})
+
+		// progress is polled instead of pushed, so the copy loop never waits on the UI
+		go func() {
+			ticker := time.NewTicker(200 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					read := src.read.Load()
+					fyne.Do(func() {
+						select {
+						case <-done:
+							return
+						default:
+						}
+
+						if total > 0 {
+							progress.SetValue(float64(read) / float64(total))
+						}
+						statusLabel.SetText(fmt.Sprintf("Processed %d bytes", read))
+					})
+				}
+			}
+		}()
+
+		go func() {
+			err := streamBase64(writer, src, decode, alphabet, padded)
+			reader.Close()
+			if closeErr := writer.Close(); err == nil {
+				err = closeErr
+			}
+			close(done)
+			cancelFunc()
+
+			fyne.Do(func() {
+				cancel = nil
+				startBtn.Enable()
+				cancelBtn.Disable()
+
+				switch {
+				case errors.Is(err, context.Canceled):
+					statusLabel.SetText("Cancelled, output file is incomplete")
+				case err != nil:
+					statusLabel.SetText("Failed")
+					dialog.ShowError(err, w)
+				default:
+					progress.SetValue(1)
+					statusLabel.SetText(fmt.Sprintf("Done, processed %d bytes", src.read.Load()))
+				}
+			})
+		}()
+	}
+
+	startBtn.OnTapped = func() {
+		if inputURI == nil || outputDir == nil {
+			dialog.ShowError(fmt.Errorf("input file and output folder must be chosen"), w)
+			return
+		}
+
+		name := strings.TrimSpace(outputNameEntry.Text)
+		if name == "" {
+			dialog.ShowError(fmt.Errorf("output file name must be fill"), w)
+			return
+		}
+		if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			dialog.ShowError(fmt.Errorf("invalid output file name: %v", name), w)
+			return
+		}
+
+		outputURI, err := storage.Child(outputDir, name)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if inputURI.String() == outputURI.String() {
+			dialog.ShowError(fmt.Errorf("input and output must be different files"), w)
+			return
+		}
+
+		if exists, _ := storage.Exists(outputURI); exists {
+			dialog.ShowConfirm("Overwrite", fmt.Sprintf("%s already exists, overwrite it?", name), func(ok bool) {
+				if ok {
+					start(outputURI)
+				}
+			}, w)
+			return
+		}
+
+		start(outputURI)
+	}
+
+	configs := widget.NewForm()
+	configs.Append("Input:", container.NewBorder(nil, nil, nil, inputBtn, inputLabel))
+	configs.Append("Output:", container.NewBorder(nil, nil, nil, outputBtn, outputLabel))
+	configs.Append("File name:", outputNameEntry)
+	configs.Append("Mode:", modeOptions)
+	configs.Append("Alphabet:", container.NewHBox(alphabetOptions, paddedCheck))
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Stream large files without loading them into memory", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		vPadding(10),
+		configs,
+		vPadding(10),
+		container.NewHBox(startBtn, cancelBtn),
+		progress,
+		statusLabel,
+	)
+
+	return padding(15, content)
+}
@@ -70,6 +70,25 @@ func envToRichText(input string) *widget.RichText {
 	return rt
 }
 
+// maskEnvValues hides the value of every KEY=value line, keys and comments stay readable.
+func maskEnvValues(input string) string {
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		lines[i] = key + "=" + maskSecret(strings.TrimSpace(value))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func envToJSON(input string) (string, error) {
 	result := make(map[string]interface{})
 
@@ -204,11 +223,13 @@ func windowEnvViewer(w fyne.Window) func() {
 		textArea.Wrapping = fyne.TextWrapBreak
 		containerValue := container.NewStack(richText)
 
-		list.OnSelected = func(id widget.ListItemID) {
-			envPath := listSourceEnvApps[id].EnvPath
-			envData := catEnvFile(envPath)
-			viewOption := viewOptions.Text
-			currentEnvData = envData
+		maskCheck := widget.NewCheck("Mask values", nil)
+
+		showEnv := func(viewOption string) {
+			envData := currentEnvData
+			if maskCheck.Checked {
+				envData = maskEnvValues(envData)
+			}
 
 			if viewOption == "Markdown" {
 				richText = envToRichText(envData)
@@ -230,25 +251,16 @@ func windowEnvViewer(w fyne.Window) func() {
 			containerValue.Refresh()
 		}
 
-		viewOptions.OnChanged = func(s string) {
-			if s == "Markdown" {
-				richText = envToRichText(currentEnvData)
-				richText.Wrapping = fyne.TextWrapBreak
-
-				containerValue.Objects = []fyne.CanvasObject{richText}
-			} else if s == "JSON" {
-				jsonString, _ := envToJSON(currentEnvData)
-				richText = jsonToRichText(jsonString)
-				richText.Wrapping = fyne.TextWrapBreak
-
-				containerValue.Objects = []fyne.CanvasObject{richText}
-			} else {
-				textArea.SetText(currentEnvData)
+		list.OnSelected = func(id widget.ListItemID) {
+			envPath := listSourceEnvApps[id].EnvPath
+			currentEnvData = catEnvFile(envPath)
 
-				containerValue.Objects = []fyne.CanvasObject{textArea}
-			}
+			showEnv(viewOptions.Text)
+		}
 
-			containerValue.Refresh()
+		viewOptions.OnChanged = showEnv
+		maskCheck.OnChanged = func(bool) {
+			showEnv(viewOptions.Text)
 		}
 
 		viewDropdown := container.NewGridWrap(fyne.NewSize(130, 40), viewOptions)
@@ -284,6 +296,17 @@ func windowEnvViewer(w fyne.Window) func() {
 		})
 
 		btnCopy := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+			// masked values are secrets: copy the real ones, but only for a while
+			if maskCheck.Checked {
+				text := currentEnvData
+				if viewOptions.Text == "JSON" {
+					text, _ = envToJSON(currentEnvData)
+				}
+
+				copySecret(w, text)
+				return
+			}
+
 			if viewOptions.Text == "Textarea" {
 				w.Clipboard().SetContent(textArea.Text)
 			} else {
@@ -297,7 +320,7 @@ func windowEnvViewer(w fyne.Window) func() {
 			Bold: true,
 		}
 
-		actionBtn := container.NewBorder(nil, nil, container.NewHBox(btnAdd, labelProject), container.NewHBox(viewDropdown, btnCopy))
+		actionBtn := container.NewBorder(nil, nil, container.NewHBox(btnAdd, labelProject), container.NewHBox(maskCheck, viewDropdown, btnCopy))
 
 		rightPanel := container.NewVScroll(containerValue)
 		leftPanel := container.NewGridWrap(fyne.NewSize(240, w.Content().Size().Height), list)
@@ -309,7 +332,7 @@ func windowEnvViewer(w fyne.Window) func() {
 
 		content := container.NewBorder(topPanel, nil, leftPanel, nil, rightPanel)
 
-		w.SetContent(
+		setToolContent(w,
 			container.NewBorder(toolbars(w), nil, nil, nil, content),
 		)
 	}
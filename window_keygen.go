@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keyFilesView shows a generated bundle file by file, private keys stay
+// masked until revealed, and saves the whole bundle into a chosen folder.
+type keyFilesView struct {
+	w       fyne.Window
+	files   []KeyFile
+	summary *widget.Label
+	tabs    *container.AppTabs
+	reveal  *widget.Check
+	saveBtn *widget.Button
+}
+
+func newKeyFilesView(w fyne.Window) *keyFilesView {
+	v := &keyFilesView{
+		w:       w,
+		summary: widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+		tabs:    container.NewAppTabs(),
+	}
+	v.summary.Wrapping = fyne.TextWrapBreak
+
+	v.reveal = widget.NewCheck("Reveal private keys", func(bool) {
+		v.Set(v.files, v.summary.Text)
+	})
+
+	v.saveBtn = widget.NewButtonWithIcon("Save to Folder", theme.FolderOpenIcon(), func() {
+		files := v.files
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if dir == nil {
+				return
+			}
+
+			if err := writeKeyFiles(dir.Path(), files); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			names := make([]string, len(files))
+			for i, file := range files {
+				names[i] = file.Name
+			}
+			dialog.ShowInformation("Saved", fmt.Sprintf("Wrote %s to %s", strings.Join(names, ", "), dir.Path()), w)
+		}, w)
+	})
+	v.saveBtn.Disable()
+
+	return v
+}
+
+func (v *keyFilesView) Set(files []KeyFile, summary string) {
+	v.files = files
+	v.summary.SetText(summary)
+
+	selected := v.tabs.SelectedIndex()
+	v.tabs.SetItems(nil)
+	for _, file := range files {
+		text := string(file.Data)
+		if file.Private && !v.reveal.Checked {
+			text = "(private key hidden, tick \"Reveal private keys\" to show it)"
+		}
+
+		area := widget.NewMultiLineEntry()
+		area.TextStyle = fyne.TextStyle{Monospace: true}
+		area.Wrapping = fyne.TextWrapBreak
+		area.SetText(text)
+		area.Disable()
+
+		copyBtn := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+			if file.Private {
+				copySecret(v.w, string(file.Data))
+				return
+			}
+			v.w.Clipboard().SetContent(string(file.Data))
+		})
+
+		v.tabs.Append(container.NewTabItem(file.Name, container.NewBorder(nil, container.NewHBox(copyBtn), nil, nil, area)))
+	}
+	if selected >= 0 && selected < len(files) {
+		v.tabs.SelectIndex(selected)
+	}
+
+	if len(files) == 0 {
+		v.saveBtn.Disable()
+	} else {
+		v.saveBtn.Enable()
+	}
+}
+
+func (v *keyFilesView) Content() fyne.CanvasObject {
+	top := container.NewVBox(
+		widget.NewLabelWithStyle("Result", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		v.summary,
+		container.NewBorder(nil, nil, nil, v.saveBtn, v.reveal),
+		widget.NewSeparator(),
+	)
+
+	return container.NewBorder(top, nil, nil, nil, v.tabs)
+}
+
+// runKeyJob generates off the UI goroutine, RSA 4096 can take a few seconds.
+func runKeyJob(w fyne.Window, btn *widget.Button, status *fyne.Container, view *keyFilesView, job func() ([]KeyFile, string, error)) {
+	btn.Disable()
+	status.Objects = []fyne.CanvasObject{statusText("Generating…", theme.ColorNameDisabled)}
+	status.Refresh()
+
+	go func() {
+		files, summary, err := job()
+
+		fyne.Do(func() {
+			btn.Enable()
+			if err != nil {
+				status.Objects = nil
+				status.Refresh()
+				dialog.ShowError(err, w)
+				return
+			}
+
+			status.Objects = []fyne.CanvasObject{statusText("✔ Generated, not saved yet", theme.ColorNameSuccess)}
+			status.Refresh()
+			view.Set(files, summary)
+		})
+	}()
+}
+
+func sshKeyTab(w fyne.Window) fyne.CanvasObject {
+	view := newKeyFilesView(w)
+	status := container.NewStack()
+
+	keyTypeOptions := widget.NewSelect(listSSHKeyType, nil)
+	keyTypeOptions.SetSelected("Ed25519")
+
+	commentEntry := widget.NewEntry()
+	commentEntry.SetPlaceHolder("user@host")
+
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Empty for no passphrase")
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Repeat passphrase")
+
+	configs := widget.NewForm()
+	configs.Append("Key Type:", keyTypeOptions)
+	configs.Append("Comment:", commentEntry)
+	configs.Append("Passphrase:", passphraseEntry)
+	configs.Append("Confirm:", confirmEntry)
+
+	var generateBtn *widget.Button
+	generateBtn = widget.NewButtonWithIcon("Generate", theme.DocumentPrintIcon(), func() {
+		if passphraseEntry.Text != confirmEntry.Text {
+			dialog.ShowError(errors.New("passphrase and confirmation do not match"), w)
+			return
+		}
+
+		kind, comment, passphrase := keyTypeOptions.Selected, commentEntry.Text, passphraseEntry.Text
+		runKeyJob(w, generateBtn, status, view, func() ([]KeyFile, string, error) {
+			files, fingerprint, err := GenerateSSHKey(kind, comment, passphrase)
+			if err != nil {
+				return nil, "", err
+			}
+
+			summary := kind + " key\nFingerprint: " + fingerprint
+			if passphrase == "" {
+				summary += "\nPrivate key is NOT encrypted"
+			}
+
+			return files, summary, nil
+		})
+	})
+
+	left := container.NewVBox(
+		widget.NewLabelWithStyle("SSH Key Pair", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		container.NewHBox(generateBtn),
+		status,
+	)
+
+	return container.NewHSplit(padding(10, left), padding(10, view.Content()))
+}
+
+func tlsCertificateTab(w fyne.Window) fyne.CanvasObject {
+	view := newKeyFilesView(w)
+	status := container.NewStack()
+
+	kindOptions := widget.NewRadioGroup(listCertKind, nil)
+	kindOptions.SetSelected("Self-signed")
+
+	keyTypeOptions := widget.NewSelect(listTLSKeyType, nil)
+	keyTypeOptions.SetSelected("ECDSA P-256")
+
+	commonNameEntry := widget.NewEntry()
+	commonNameEntry.SetText("localhost")
+
+	organizationEntry := widget.NewEntry()
+	organizationEntry.SetPlaceHolder("Optional")
+
+	sansEntry := widget.NewMultiLineEntry()
+	sansEntry.SetPlaceHolder("One per line or comma separated:\nlocalhost, *.local.test, 127.0.0.1, ::1, dev@example.com")
+	sansEntry.SetText("localhost\n127.0.0.1\n::1")
+	sansEntry.SetMinRowsVisible(4)
+
+	validEntry := widget.NewEntry()
+	validEntry.SetText("365")
+
+	configs := widget.NewForm()
+	configs.Append("Kind:", kindOptions)
+	configs.Append("Key Type:", keyTypeOptions)
+	configs.Append("Common Name:", commonNameEntry)
+	configs.Append("Organization:", organizationEntry)
+	configs.Append("SANs:", sansEntry)
+	configs.Append("Valid (days):", validEntry)
+
+	var generateBtn *widget.Button
+	generateBtn = widget.NewButtonWithIcon("Generate", theme.DocumentPrintIcon(), func() {
+		days := 0
+		if _, err := fmt.Sscan(validEntry.Text, &days); err != nil {
+			dialog.ShowError(fmt.Errorf("invalid validity: %v", validEntry.Text), w)
+			return
+		}
+
+		opts := CertOptions{
+			CommonName:   strings.TrimSpace(commonNameEntry.Text),
+			Organization: strings.TrimSpace(organizationEntry.Text),
+			SANs:         []string{sansEntry.Text},
+			ValidDays:    days,
+			KeyType:      keyTypeOptions.Selected,
+			Kind:         kindOptions.Selected,
+		}
+
+		// catch typos before the slow key generation
+		if _, _, _, err := parseSANs(sansEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		runKeyJob(w, generateBtn, status, view, func() ([]KeyFile, string, error) {
+			return GenerateCertificate(opts)
+		})
+	})
+
+	left := container.NewVBox(
+		widget.NewLabelWithStyle("TLS Certificate", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		container.NewHBox(generateBtn),
+		status,
+	)
+
+	return container.NewHSplit(padding(10, container.NewVScroll(left)), padding(10, view.Content()))
+}
+
+func windowKeyGenerator(w fyne.Window) func() {
+	return func() {
+		notePreviewToolbar.ToolbarObject().Hide()
+
+		tabs := container.NewAppTabs(
+			container.NewTabItemWithIcon("SSH Key", theme.LoginIcon(), sshKeyTab(w)),
+			container.NewTabItemWithIcon("TLS Certificate", theme.DocumentIcon(), tlsCertificateTab(w)),
+		)
+
+		setToolContent(w,
+			container.NewBorder(toolbars(w), nil, nil, nil, tabs),
+		)
+	}
+}
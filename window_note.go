@@ -158,7 +158,7 @@ func windowNote(w fyne.Window) func() {
 		split := container.NewHSplit(padding(5, leftPannel), padding(5, rightPanel))
 		split.SetOffset(0.3)
 
-		w.SetContent(
+		setToolContent(w,
 			container.NewBorder(toolbars(w), nil, nil, nil, split),
 		)
 	}
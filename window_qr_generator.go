@@ -3,7 +3,9 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"image"
 	"io"
+	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -12,24 +14,62 @@ import (
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"github.com/yeqown/go-qrcode/v2"
 	"github.com/yeqown/go-qrcode/writer/standard"
 )
 
-var listQRLogoName = []string{"Sebari", "Linkreator"}
-var listQRLogoAssets = map[string]string{
-	"Sebari":     "assets/sebari_logo_qr_code.png",
-	"Linkreator": "assets/linkreator_logo_qr_code.png",
+// listQRLogos is the logo library, loaded when the QR Generator first opens.
+var listQRLogos []QRLogo
+
+func qrLogoNames() []string {
+	names := []string{qrNoLogo}
+	for _, logo := range listQRLogos {
+		names = append(names, logo.Name)
+	}
+
+	return names
 }
 
-func generateQR(text string, logoName string, widthImage uint8, widthBorder int) ([]byte, error) {
-	qr, err := qrcode.New(text)
+// checkHalftone decodes the halftone the way the writer will.
+func checkHalftone(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _, err = image.Decode(f)
+	return err
+}
+
+func generateQR(text string, opts QROptions) ([]byte, error) {
+	qr, err := opts.encodeQR(text)
 	if err != nil {
 		return nil, err
 	}
 
+	// the writer only prints a halftone it can't read, surface it instead
+	if opts.Halftone != "" {
+		if err := checkHalftone(opts.Halftone); err != nil {
+			return nil, fmt.Errorf("halftone image: %w", err)
+		}
+	}
+
 	var bufQRImage bytes.Buffer
-	var logoAssets = listQRLogoAssets[logoName]
+
+	options := []standard.ImageOption{
+		standard.WithBuiltinImageEncoder(standard.PNG_FORMAT),
+		standard.WithQRWidth(opts.ModuleWidth),
+		standard.WithBorderWidth(opts.Border),
+	}
+	options = append(options, opts.imageOptions()...)
+	if logo, ok := findQRLogo(listQRLogos, opts.Logo); ok {
+		img, err := renderQRLogo(logo, qr.Dimension()*int(opts.ModuleWidth), opts.logoBackground())
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, standard.WithLogoImage(img), standard.WithLogoSizeMultiplier(logo.SizeMultiplier))
+	}
 
 	pr, pw := io.Pipe()
 
@@ -37,12 +77,7 @@ func generateQR(text string, logoName string, widthImage uint8, widthBorder int)
 	defer pw.Close()
 
 	go func() {
-		writer := standard.NewWithWriter(pw,
-			standard.WithQRWidth(widthImage),
-			standard.WithBorderWidth(widthBorder),
-			standard.WithLogoImageFilePNG(logoAssets),
-			standard.WithLogoSizeMultiplier(1),
-		)
+		writer := standard.NewWithWriter(pw, options...)
 
 		err := qr.Save(writer)
 		if err != nil {
@@ -69,8 +104,45 @@ func windowQRGenerator(w fyne.Window) func() {
 		widthImageEntry.SetPlaceHolder("Width Image")
 		widthImageEntry.SetText("15")
 
-		logoOptions := widget.NewSelectEntry(listQRLogoName)
-		logoOptions.SetText("Sebari")
+		if listQRLogos == nil {
+			logos, err := loadQRLogos()
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("load logo library: %w", err), w)
+			}
+			listQRLogos = logos
+		}
+
+		errorCorrectionOptions := widget.NewSelect(listQRErrorCorrection, nil)
+		errorCorrectionOptions.SetSelected(defaultQROptions.ErrorCorrection)
+
+		encodingModeOptions := widget.NewSelect(listQREncodingMode, nil)
+		encodingModeOptions.SetSelected(defaultQROptions.EncodingMode)
+
+		minVersionOptions := widget.NewSelect(listQRVersion, nil)
+		minVersionOptions.SetSelected("Auto")
+
+		// a logo hides the modules under it, only High recovers enough of them
+		logoOptions := widget.NewSelect(qrLogoNames(), func(selected string) {
+			if selected != qrNoLogo {
+				errorCorrectionOptions.SetSelected("High (30%)")
+			}
+		})
+		if len(listQRLogos) > 0 {
+			logoOptions.SetSelected(listQRLogos[0].Name)
+		} else {
+			logoOptions.SetSelected(qrNoLogo)
+		}
+
+		manageLogosBtn := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
+			showQRLogoLibrary(w, func() {
+				selected := logoOptions.Selected
+				logoOptions.SetOptions(qrLogoNames())
+				if _, ok := findQRLogo(listQRLogos, selected); !ok {
+					selected = qrNoLogo
+				}
+				logoOptions.SetSelected(selected)
+			})
+		})
 
 		widthBorderEntry := widget.NewEntry()
 		widthBorderEntry.SetPlaceHolder("Width Border")
@@ -78,6 +150,216 @@ func windowQRGenerator(w fyne.Window) func() {
 
 		resultImage := canvas.NewImageFromResource(theme.BrokenImageIcon())
 
+		applyEncoding := func(opts *QROptions) {
+			opts.ErrorCorrection = errorCorrectionOptions.Selected
+			opts.EncodingMode = encodingModeOptions.Selected
+			opts.MinVersion = 0
+			fmt.Sscan(minVersionOptions.Selected, &opts.MinVersion)
+		}
+
+		// version and capacity, refreshed as the text or the encoding changes
+		infoStack := container.NewStack()
+		checkEncoding := func() {
+			opts := defaultQROptions
+			applyEncoding(&opts)
+
+			var status fyne.CanvasObject
+			if info, err := opts.Info(textEntry.Text); err != nil {
+				status = statusText("✘ "+err.Error(), theme.ColorNameError)
+			} else if logoOptions.Selected != qrNoLogo && opts.ErrorCorrection != "High (30%)" {
+				status = statusText("⚠ "+info.String()+", use High error correction with a logo", theme.ColorNameWarning)
+			} else {
+				status = statusText(info.String(), theme.ColorNameForeground)
+			}
+
+			infoStack.Objects = []fyne.CanvasObject{status}
+			infoStack.Refresh()
+		}
+
+		textEntry.OnChanged = func(string) { checkEncoding() }
+
+		// templates assemble the text from form fields as they are edited
+		templateStack := container.NewStack()
+		payloadStack := container.NewStack()
+		var payload QRPayload
+		var getPayload func() map[string]string
+		var setPayload func(map[string]string)
+
+		buildPayload := func() {
+			if getPayload == nil {
+				return
+			}
+
+			text, err := payload.Build(getPayload())
+			if err != nil {
+				payloadStack.Objects = []fyne.CanvasObject{statusText("✘ "+err.Error(), theme.ColorNameError)}
+				payloadStack.Refresh()
+				return
+			}
+
+			payloadStack.Objects = nil
+			payloadStack.Refresh()
+			textEntry.SetText(text)
+		}
+
+		templateOptions := widget.NewSelect(listQRPayloadType, func(name string) {
+			payloadStack.Objects = nil
+			payloadStack.Refresh()
+
+			var ok bool
+			if payload, ok = findQRPayload(name); !ok {
+				templateStack.Objects = nil
+				templateStack.Refresh()
+				return
+			}
+
+			var form *widget.Form
+			form, getPayload, setPayload = qrPayloadForm(payload, buildPayload)
+			templateStack.Objects = []fyne.CanvasObject{form}
+			templateStack.Refresh()
+		})
+		templateOptions.SetSelected(qrPayloadText)
+
+		readPayloadBtn := widget.NewButtonWithIcon("Read From Text", theme.ViewRefreshIcon(), func() {
+			p, values, err := detectQRPayload(textEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			templateOptions.SetSelected(p.Name)
+			setPayload(values)
+		})
+		errorCorrectionOptions.OnChanged = func(string) { checkEncoding() }
+		encodingModeOptions.OnChanged = func(string) { checkEncoding() }
+		minVersionOptions.OnChanged = func(string) { checkEncoding() }
+		checkEncoding()
+
+		// contrast warning, refreshed on every style edit
+		contrastStack := container.NewStack()
+		var applyStyle func(opts *QROptions) error
+		checkStyle := func() {
+			if applyStyle == nil {
+				return
+			}
+
+			opts := defaultQROptions
+			var status fyne.CanvasObject
+			if err := applyStyle(&opts); err != nil {
+				status = statusText("✘ "+err.Error(), theme.ColorNameError)
+			} else if err := opts.CheckContrast(); err != nil {
+				status = statusText("⚠ May not scan: "+err.Error(), theme.ColorNameWarning)
+			} else {
+				status = statusText("✔ Contrast looks good", theme.ColorNameSuccess)
+			}
+
+			contrastStack.Objects = []fyne.CanvasObject{status}
+			contrastStack.Refresh()
+		}
+
+		var styleForm *widget.Form
+		styleForm, applyStyle = qrStyleForm(w, checkStyle)
+		checkStyle()
+
+		// the last generated QR and the options it was made with, for saving
+		var lastQR []byte
+		var lastText string
+		var lastOpts QROptions
+
+		exportFormatOptions := widget.NewSelect(listQRExportFormat, nil)
+		exportFormatOptions.SetSelected("PNG")
+
+		exportSizeEntry := widget.NewEntry()
+		exportSizeEntry.SetPlaceHolder("Size (px)")
+		exportSizeEntry.SetText("1024")
+
+		saveBtn := widget.NewButtonWithIcon("Save As", theme.DocumentSaveIcon(), nil)
+		copyImageBtn := widget.NewButtonWithIcon("Copy Image", theme.ContentCopyIcon(), nil)
+		saveBtn.Disable()
+		copyImageBtn.Disable()
+
+		parseExportSize := func() (int, error) {
+			size := 0
+
+			_, err := fmt.Sscan(exportSizeEntry.Text, &size)
+			if err != nil || size <= 0 || size > qrExportMaxSize {
+				return 0, fmt.Errorf("invalid size: %v", exportSizeEntry.Text)
+			}
+
+			return size, nil
+		}
+
+		exportQR := func(format string, size int) ([]byte, error) {
+			if format != "SVG" {
+				return EncodeQRImage(lastQR, format, size, lastOpts)
+			}
+
+			bitmap, err := qrBitmap(lastText, lastOpts)
+			if err != nil {
+				return nil, err
+			}
+
+			svgOpts, err := lastOpts.svgOptions(size, len(bitmap))
+			if err != nil {
+				return nil, err
+			}
+
+			return QRSVG(bitmap, svgOpts)
+		}
+
+		saveBtn.OnTapped = func() {
+			size, err := parseExportSize()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			format := exportFormatOptions.Selected
+			data, err := exportQR(format, size)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if writer == nil {
+					return
+				}
+				defer writer.Close()
+
+				if _, err := writer.Write(data); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+
+				dialog.ShowInformation("Save", "QR has been saved to "+writer.URI().Name()+" ✔", w)
+			}, w)
+			save.SetFileName("qrcode" + qrExportExtension(format))
+			save.Show()
+		}
+
+		copyImageBtn.OnTapped = func() {
+			size, err := parseExportSize()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			data, err := EncodeQRImage(lastQR, "PNG", size, lastOpts)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			if err := copyImage(data); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}
+
 		generate := func() {
 			var widthImage uint8
 			var widthBorder int
@@ -99,7 +381,15 @@ func windowQRGenerator(w fyne.Window) func() {
 				return
 			}
 
-			qrCode, err := generateQR(textEntry.Text, logoOptions.Text, widthImage, widthBorder)
+			opts := defaultQROptions
+			opts.Logo, opts.ModuleWidth, opts.Border = logoOptions.Selected, widthImage, widthBorder
+			applyEncoding(&opts)
+			if err := applyStyle(&opts); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			qrCode, err := generateQR(textEntry.Text, opts)
 			if err != nil {
 				dialog.ShowError(err, w)
 				return
@@ -111,6 +401,10 @@ func windowQRGenerator(w fyne.Window) func() {
 			resultImage.FillMode = canvas.ImageFillContain
 			resultImage.Refresh()
 
+			lastQR, lastText, lastOpts = qrCode, textEntry.Text, opts
+			saveBtn.Enable()
+			copyImageBtn.Enable()
+
 			dialog.ShowInformation("Generate", "QR has been Generated ✔", w)
 		}
 
@@ -122,8 +416,11 @@ func windowQRGenerator(w fyne.Window) func() {
 			resultImage.Resource = brokenImage.Resource
 			resultImage.Refresh()
 
-			textEntry.Text = ""
-			textEntry.Refresh()
+			lastQR = nil
+			saveBtn.Disable()
+			copyImageBtn.Disable()
+
+			textEntry.SetText("")
 		})
 
 		// shortcuts: Ctrl+G for generate
@@ -133,18 +430,39 @@ func windowQRGenerator(w fyne.Window) func() {
 			})
 		}
 
+		templateConfigs := widget.NewForm()
+		templateConfigs.Append("Template:", container.NewBorder(nil, nil, nil, readPayloadBtn, templateOptions))
+
 		configs := widget.NewForm()
 		configs.Append("Text:", textEntry)
 		configs.Append("Width Image:", widthImageEntry)
 		configs.Append("Width Border:", widthBorderEntry)
-		configs.Append("Logo:", logoOptions)
+		configs.Append("Logo:", container.NewBorder(nil, nil, nil, manageLogosBtn, logoOptions))
+
+		encodingConfigs := widget.NewForm()
+		encodingConfigs.Append("Error Correction:", errorCorrectionOptions)
+		encodingConfigs.Append("Mode:", encodingModeOptions)
+		encodingConfigs.Append("Min Version:", minVersionOptions)
 
 		left := container.NewVBox(
 			widget.NewLabelWithStyle("Configuration", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 			widget.NewSeparator(),
 			vPadding(10),
+			templateConfigs,
+			templateStack,
+			payloadStack,
 			configs,
 			vPadding(10),
+			widget.NewLabelWithStyle("Encoding", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			encodingConfigs,
+			infoStack,
+			vPadding(10),
+			widget.NewLabelWithStyle("Style", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			styleForm,
+			contrastStack,
+			vPadding(10),
 			widget.NewSeparator(),
 			vPadding(10),
 			container.NewHBox(generateBtn, clearBtn),
@@ -152,16 +470,26 @@ func windowQRGenerator(w fyne.Window) func() {
 
 		imageCard := container.NewGridWrap(fyne.NewSize(300, 300), resultImage)
 
+		exportConfigs := widget.NewForm()
+		exportConfigs.Append("Format:", exportFormatOptions)
+		exportConfigs.Append("Size (px):", exportSizeEntry)
+
+		exportBox := container.NewVBox(
+			widget.NewSeparator(),
+			exportConfigs,
+			container.NewHBox(saveBtn, copyImageBtn),
+		)
+
 		resultLabel := widget.NewLabelWithStyle("Result", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-		right := container.NewBorder(resultLabel, nil, nil, nil, container.NewCenter(imageCard))
+		right := container.NewBorder(resultLabel, exportBox, nil, nil, container.NewCenter(imageCard))
 
 		rightPanel := padding(10, right)
-		leftPanel := padding(15, left)
+		leftPanel := padding(15, container.NewVScroll(left))
 
 		split := container.NewHSplit(leftPanel, rightPanel)
 		split.SetOffset(0.7)
 
-		w.SetContent(
+		setToolContent(w,
 			container.NewBorder(toolbars(w), nil, nil, nil, split),
 		)
 	}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var listQRLogoSize = []string{"1/2", "1/3", "1/4", "1/5", "1/6", "1/7", "1/8", "1/9", "1/10"}
+
+// qrLogoPreviewPixels is the code width the preview renders the logo for.
+const qrLogoPreviewPixels = 600
+
+// showQRLogoLibrary lets the user add, tune and delete logos. Every change is
+// saved straight away, onChange refreshes the caller's logo select.
+func showQRLogoLibrary(w fyne.Window, onChange func()) {
+	selected := -1
+
+	preview := canvas.NewImageFromResource(theme.BrokenImageIcon())
+	preview.FillMode = canvas.ImageFillContain
+	previewBg := canvas.NewRectangle(color.White)
+	previewStatus := container.NewStack()
+
+	nameEntry := widget.NewEntry()
+	sizeOptions := widget.NewSelect(listQRLogoSize, nil)
+	paddingEntry := widget.NewEntry()
+	paddingEntry.SetPlaceHolder("Padding (px)")
+
+	applyBtn := widget.NewButtonWithIcon("Apply", theme.ConfirmIcon(), nil)
+	deleteBtn := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), nil)
+
+	details := widget.NewForm()
+	details.Append("Name:", nameEntry)
+	details.Append("Size:", sizeOptions)
+	details.Append("Padding:", paddingEntry)
+
+	list := widget.NewList(
+		func() int {
+			return len(listQRLogos)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			logo := listQRLogos[i]
+			o.(*widget.Label).SetText(fmt.Sprintf("%s  (1/%d, %dpx)", logo.Name, logo.SizeMultiplier, logo.Padding))
+		},
+	)
+
+	showPreview := func(logo QRLogo) {
+		img, err := renderQRLogo(logo, qrLogoPreviewPixels, color.White)
+		if err != nil {
+			preview.Image = nil
+			preview.Resource = theme.BrokenImageIcon()
+			previewStatus.Objects = []fyne.CanvasObject{statusText("✘ "+err.Error(), theme.ColorNameError)}
+		} else {
+			preview.Resource = nil
+			preview.Image = img
+			previewStatus.Objects = nil
+		}
+		preview.Refresh()
+		previewStatus.Refresh()
+	}
+
+	showDetails := func() {
+		if selected < 0 || selected >= len(listQRLogos) {
+			nameEntry.SetText("")
+			paddingEntry.SetText("")
+			applyBtn.Disable()
+			deleteBtn.Disable()
+
+			preview.Image = nil
+			preview.Resource = theme.BrokenImageIcon()
+			preview.Refresh()
+			previewStatus.Objects = nil
+			previewStatus.Refresh()
+			return
+		}
+
+		logo := listQRLogos[selected]
+		nameEntry.SetText(logo.Name)
+		sizeOptions.SetSelected(fmt.Sprintf("1/%d", logo.SizeMultiplier))
+		paddingEntry.SetText(fmt.Sprint(logo.Padding))
+		applyBtn.Enable()
+		deleteBtn.Enable()
+		showPreview(logo)
+	}
+
+	commit := func(logos []QRLogo) bool {
+		if err := saveQRLogos(logos); err != nil {
+			dialog.ShowError(err, w)
+			return false
+		}
+
+		listQRLogos = logos
+		list.Refresh()
+		onChange()
+
+		return true
+	}
+
+	uniqueName := func(name string, except int) error {
+		for i, logo := range listQRLogos {
+			if i != except && strings.EqualFold(logo.Name, name) {
+				return fmt.Errorf("a logo named %q already exists", name)
+			}
+		}
+
+		return nil
+	}
+
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		showDetails()
+	}
+
+	applyBtn.OnTapped = func() {
+		logo := listQRLogos[selected]
+		logo.Name = strings.TrimSpace(nameEntry.Text)
+
+		if _, err := fmt.Sscanf(sizeOptions.Selected, "1/%d", &logo.SizeMultiplier); err != nil {
+			dialog.ShowError(fmt.Errorf("invalid size: %v", sizeOptions.Selected), w)
+			return
+		}
+		if _, err := fmt.Sscan(paddingEntry.Text, &logo.Padding); err != nil {
+			dialog.ShowError(fmt.Errorf("invalid padding: %v", paddingEntry.Text), w)
+			return
+		}
+		if err := logo.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if err := uniqueName(logo.Name, selected); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		logos := append([]QRLogo{}, listQRLogos...)
+		logos[selected] = logo
+		if commit(logos) {
+			showPreview(logo)
+		}
+	}
+
+	deleteBtn.OnTapped = func() {
+		logo := listQRLogos[selected]
+
+		dialog.ShowConfirm("Delete Logo", fmt.Sprintf("Delete %q from the library?", logo.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+
+			logos := append(append([]QRLogo{}, listQRLogos[:selected]...), listQRLogos[selected+1:]...)
+			if !commit(logos) {
+				return
+			}
+			if err := removeQRLogoFile(logo); err != nil {
+				dialog.ShowError(err, w)
+			}
+
+			list.UnselectAll()
+			selected = -1
+			showDetails()
+		}, w)
+	}
+
+	addBtn := widget.NewButtonWithIcon("Add Logo", theme.ContentAddIcon(), func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			// the entry is named after the file, unless that name is empty or reserved
+			ext := reader.URI().Extension()
+			base := strings.TrimSpace(strings.TrimSuffix(reader.URI().Name(), ext))
+			if base == "" || strings.EqualFold(base, qrNoLogo) {
+				base = "Logo"
+			}
+			name := base
+			for i := 2; uniqueName(name, -1) != nil; i++ {
+				name = fmt.Sprintf("%s %d", base, i)
+			}
+
+			logo := QRLogo{Name: name, SizeMultiplier: 5, Padding: 4}
+			if err := logo.Validate(); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			logo.File, err = importQRLogo(name, data, ext)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			logos := append(append([]QRLogo{}, listQRLogos...), logo)
+			if commit(logos) {
+				list.Select(len(logos) - 1)
+			}
+		}, w)
+		open.SetFilter(storage.NewExtensionFileFilter(listQRLogoExtension))
+		open.Show()
+	})
+
+	showDetails()
+
+	previewBox := container.NewGridWrap(fyne.NewSize(160, 160), container.NewStack(previewBg, padding(8, preview)))
+	right := container.NewVBox(
+		container.NewCenter(previewBox),
+		previewStatus,
+		details,
+		container.NewHBox(applyBtn, deleteBtn),
+	)
+
+	left := container.NewBorder(container.NewHBox(addBtn), nil, nil, nil, list)
+	split := container.NewHSplit(left, padding(10, right))
+	split.SetOffset(0.45)
+
+	d := dialog.NewCustom("Logo Library", "Close", split, w)
+	d.Resize(fyne.NewSize(720, 460))
+	d.Show()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// qrPayloadForm builds the fields of a template. get reads the values, set
+// fills them without firing onChange, so reading a payload doesn't rewrite it.
+func qrPayloadForm(p QRPayload, onChange func()) (*widget.Form, func() map[string]string, func(map[string]string)) {
+	// SetSelected fires OnChanged, so the form counts as filling until built
+	filling := true
+	changed := func() {
+		if !filling && onChange != nil {
+			onChange()
+		}
+	}
+
+	form := widget.NewForm()
+	getters := map[string]func() string{}
+	setters := map[string]func(string){}
+
+	for _, field := range p.Fields {
+		switch {
+		case field.Check:
+			check := widget.NewCheck(field.Label, func(bool) { changed() })
+			getters[field.Key] = func() string {
+				if check.Checked {
+					return "true"
+				}
+				return ""
+			}
+			setters[field.Key] = func(value string) { check.SetChecked(value == "true") }
+			form.Append("", check)
+
+		case field.Options != nil:
+			options := widget.NewSelect(field.Options, func(string) { changed() })
+			options.SetSelected(field.Options[0])
+			getters[field.Key] = func() string { return options.Selected }
+			setters[field.Key] = func(value string) {
+				if value == "" {
+					value = field.Options[0]
+				}
+				options.SetSelected(value)
+			}
+			form.Append(field.Label+":", options)
+
+		default:
+			entry := widget.NewEntry()
+			if field.Multiline {
+				entry = widget.NewMultiLineEntry()
+				entry.Wrapping = fyne.TextWrapWord
+			}
+			entry.SetPlaceHolder(field.PlaceHolder)
+			entry.OnChanged = func(string) { changed() }
+			getters[field.Key] = func() string { return entry.Text }
+			setters[field.Key] = entry.SetText
+			form.Append(field.Label+":", entry)
+		}
+	}
+
+	filling = false
+
+	get := func() map[string]string {
+		values := map[string]string{}
+		for key, getter := range getters {
+			values[key] = getter()
+		}
+
+		return values
+	}
+
+	set := func(values map[string]string) {
+		filling = true
+		for key, setter := range setters {
+			setter(values[key])
+		}
+		filling = false
+	}
+
+	return form, get, set
+}
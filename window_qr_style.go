@@ -0,0 +1,153 @@
+package main
+
+import (
+	"image/color"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// colorField is a hex entry with a swatch and a button opening the color picker.
+func colorField(w fyne.Window, title string, initial color.NRGBA, onChange func()) (fyne.CanvasObject, *widget.Entry) {
+	swatch := canvas.NewRectangle(initial)
+	swatch.SetMinSize(fyne.NewSize(28, 28))
+	swatch.StrokeColor = theme.Color(theme.ColorNameInputBorder)
+	swatch.StrokeWidth = 1
+
+	entry := widget.NewEntry()
+	entry.SetText(colorHex(initial))
+	entry.OnChanged = func(text string) {
+		if c, err := parseColorHex(text); err == nil {
+			swatch.FillColor = c
+			swatch.Refresh()
+		}
+		onChange()
+	}
+
+	pickBtn := widget.NewButtonWithIcon("", theme.ColorPaletteIcon(), func() {
+		picker := dialog.NewColorPicker(title, "Pick a color", func(c color.Color) {
+			entry.SetText(colorHex(c))
+		}, w)
+		picker.Advanced = true
+		if c, err := parseColorHex(entry.Text); err == nil {
+			picker.SetColor(c)
+		}
+		picker.Show()
+	})
+
+	return container.NewBorder(nil, nil, container.NewCenter(swatch), pickBtn, entry), entry
+}
+
+// qrStyleForm holds the look of the code. apply copies it into opts and fails
+// on an unreadable color, onChange fires on every edit for the contrast check.
+func qrStyleForm(w fyne.Window, onChange func()) (*widget.Form, func(opts *QROptions) error) {
+	changed := func() {
+		if onChange != nil {
+			onChange()
+		}
+	}
+
+	fgField, fgEntry := colorField(w, "Foreground", defaultQROptions.Foreground, changed)
+	bgField, bgEntry := colorField(w, "Background", defaultQROptions.Background, changed)
+	gradientField, gradientEntry := colorField(w, "Gradient End", defaultQROptions.GradientEnd, changed)
+	gradientEntry.Disable()
+
+	transparentCheck := widget.NewCheck("Transparent background", func(checked bool) {
+		if checked {
+			bgEntry.Disable()
+		} else {
+			bgEntry.Enable()
+		}
+		changed()
+	})
+
+	gradientCheck := widget.NewCheck("Gradient", func(checked bool) {
+		if checked {
+			gradientEntry.Enable()
+		} else {
+			gradientEntry.Disable()
+		}
+		changed()
+	})
+
+	shapeOptions := widget.NewSelect(listQRModuleShape, func(string) { changed() })
+	shapeOptions.SetSelected(defaultQROptions.Shape)
+
+	finderOptions := widget.NewSelect(listQRFinderStyle, func(string) { changed() })
+	finderOptions.SetSelected(defaultQROptions.Finder)
+
+	halftonePath := ""
+	halftoneLabel := widget.NewLabel("None")
+	halftoneLabel.Truncation = fyne.TextTruncateEllipsis
+
+	var clearHalftoneBtn *widget.Button
+	clearHalftoneBtn = widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		halftonePath = ""
+		halftoneLabel.SetText("None")
+		clearHalftoneBtn.Disable()
+		changed()
+	})
+	clearHalftoneBtn.Disable()
+
+	chooseHalftoneBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			reader.Close()
+
+			// the writer reads the halftone from disk itself
+			halftonePath = reader.URI().Path()
+			halftoneLabel.SetText(filepath.Base(halftonePath))
+			clearHalftoneBtn.Enable()
+			changed()
+		}, w)
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+		open.Show()
+	})
+
+	form := widget.NewForm()
+	form.Append("Foreground:", fgField)
+	form.Append("", gradientCheck)
+	form.Append("Gradient End:", gradientField)
+	form.Append("Background:", bgField)
+	form.Append("", transparentCheck)
+	form.Append("Modules:", shapeOptions)
+	form.Append("Finders:", finderOptions)
+	form.Append("Halftone:", container.NewBorder(nil, nil, nil, container.NewHBox(chooseHalftoneBtn, clearHalftoneBtn), halftoneLabel))
+
+	apply := func(opts *QROptions) error {
+		fg, err := parseColorHex(fgEntry.Text)
+		if err != nil {
+			return err
+		}
+		bg, err := parseColorHex(bgEntry.Text)
+		if err != nil {
+			return err
+		}
+		gradientEnd, err := parseColorHex(gradientEntry.Text)
+		if err != nil && gradientCheck.Checked {
+			return err
+		}
+
+		opts.Foreground, opts.Background = fg, bg
+		opts.Transparent = transparentCheck.Checked
+		opts.Gradient, opts.GradientEnd = gradientCheck.Checked, gradientEnd
+		opts.Shape, opts.Finder = shapeOptions.Selected, finderOptions.Selected
+		opts.Halftone = halftonePath
+
+		return nil
+	}
+
+	return form, apply
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// exportPanel saves the shown results, or streams a fresh batch of count
+// values from the current mode straight to disk. When passthrough reports ok
+// the mode's lines are written as generated, in its own file type. The shown
+// results use shownPassthrough, taken from the mode that generated them.
+func exportPanel(w fyne.Window, results func() []string, count func() (int, error),
+	generator func() (func(count int) ([]string, string, error), error),
+	passthrough, shownPassthrough func() (extension string, headerLines int, ok bool)) fyne.CanvasObject {
+	var cancel context.CancelFunc
+
+	prefixEntry := widget.NewEntry()
+	prefixEntry.SetText("SECRET")
+
+	formatOptions := widget.NewSelect(listExportFormat, func(format string) {
+		if format == ".env" {
+			prefixEntry.Enable()
+		} else {
+			prefixEntry.Disable()
+		}
+	})
+	formatOptions.SetSelected("TXT")
+
+	progress := widget.NewProgressBar()
+	progress.Hide()
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	// exportFormat is what a mode is written as, with its extension
+	exportFormat := func(passthrough func() (string, int, bool)) (format, extension string, headerLines int) {
+		if extension, headerLines, ok := passthrough(); ok {
+			return exportAsGenerated, extension, headerLines
+		}
+
+		return formatOptions.Selected, exportExtension(formatOptions.Selected), 0
+	}
+
+	saveDialog := func(extension string, onWriter func(writer fyne.URIWriteCloser)) {
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if writer == nil {
+				return
+			}
+
+			onWriter(writer)
+		}, w)
+		save.SetFileName("strings" + extension)
+		save.Show()
+	}
+
+	exportBtn := widget.NewButtonWithIcon("Export Results", theme.DocumentSaveIcon(), func() {
+		values := results()
+		if len(values) == 0 {
+			dialog.ShowError(errors.New("nothing to export, generate some strings first"), w)
+			return
+		}
+
+		format, extension, _ := exportFormat(shownPassthrough)
+		prefix := prefixEntry.Text
+		if format == ".env" {
+			if _, err := envKeyPrefix(prefix); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+
+		saveDialog(extension, func(writer fyne.URIWriteCloser) {
+			defer writer.Close()
+
+			e, err := NewExportWriter(writer, format, prefix)
+			if err == nil {
+				err = e.Write(values)
+			}
+			if err == nil {
+				err = e.Close()
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			statusLabel.SetText(fmt.Sprintf("Exported %d values to %s", len(values), writer.URI().Name()))
+		})
+	})
+
+	streamBtn := widget.NewButtonWithIcon("Generate to File", theme.DownloadIcon(), nil)
+	cancelBtn := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	cancelBtn.Disable()
+
+	streamBtn.OnTapped = func() {
+		total, err := count()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		format, extension, headerLines := exportFormat(passthrough)
+		prefix := prefixEntry.Text
+		if format == ".env" {
+			if _, err := envKeyPrefix(prefix); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+
+		modeGenerate, err := generator()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		// mode generators read their option widgets, so every batch runs on the UI thread
+		generate := func(n int) (values []string, info string, err error) {
+			fyne.DoAndWait(func() {
+				values, info, err = modeGenerate(n)
+			})
+			return values, info, err
+		}
+
+		saveDialog(extension, func(writer fyne.URIWriteCloser) {
+			ctx, cancelFunc := context.WithCancel(context.Background())
+			cancel = cancelFunc
+			finished := false
+
+			progress.SetValue(0)
+			progress.Show()
+			statusLabel.SetText("Generating...")
+			streamBtn.Disable()
+			exportBtn.Disable()
+			cancelBtn.Enable()
+
+			go func() {
+				err := StreamExport(ctx, writer, format, prefix, total, headerLines, generate, func(done int) {
+					fyne.Do(func() {
+						if finished {
+							return
+						}
+
+						progress.SetValue(float64(done) / float64(total))
+						statusLabel.SetText(fmt.Sprintf("Written %d of %d values", done, total))
+					})
+				})
+				if closeErr := writer.Close(); err == nil {
+					err = closeErr
+				}
+				cancelFunc()
+
+				fyne.Do(func() {
+					finished = true
+					cancel = nil
+					streamBtn.Enable()
+					exportBtn.Enable()
+					cancelBtn.Disable()
+
+					switch {
+					case errors.Is(err, context.Canceled):
+						statusLabel.SetText("Cancelled, output file is incomplete")
+					case err != nil:
+						statusLabel.SetText("Failed")
+						dialog.ShowError(err, w)
+					default:
+						progress.SetValue(1)
+						statusLabel.SetText(fmt.Sprintf("Done, wrote %d values to %s", total, writer.URI().Name()))
+					}
+				})
+			}()
+		})
+	}
+
+	configs := widget.NewForm()
+	configs.Append("Format:", formatOptions)
+	configs.Append("Key prefix:", prefixEntry)
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle("Export", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		container.NewHBox(exportBtn, streamBtn, cancelBtn),
+		progress,
+		statusLabel,
+	)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fakeDataModePanel also returns how its output is exported: rows are already
+// CSV, JSON Lines or SQL, so they are written as generated.
+func fakeDataModePanel(w fyne.Window) (fyne.CanvasObject, func(count int) ([]string, string, error), func() (string, int)) {
+	var localeNames []string
+	for _, locale := range listFakeLocale {
+		localeNames = append(localeNames, locale.Name)
+	}
+
+	localeOptions := widget.NewSelect(localeNames, nil)
+	localeOptions.SetSelected(localeNames[0])
+
+	fieldOptions := widget.NewCheckGroup(listFakeField, nil)
+	fieldOptions.Horizontal = true
+	fieldOptions.SetSelected([]string{"Name", "Email", "Phone", "Address"})
+
+	tableEntry := widget.NewEntry()
+	tableEntry.SetText("users")
+
+	formatOptions := widget.NewSelect(listFakeFormat, func(format string) {
+		if format == "SQL INSERT" {
+			tableEntry.Enable()
+		} else {
+			tableEntry.Disable()
+		}
+	})
+	formatOptions.SetSelected("CSV")
+
+	configs := widget.NewForm()
+	configs.Append("Locale:", localeOptions)
+	configs.Append("Format:", formatOptions)
+	configs.Append("Table:", tableEntry)
+
+	panel := container.NewVBox(
+		widget.NewLabelWithStyle("Fake Data", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		widget.NewLabel("Fields:"),
+		fieldOptions,
+		widget.NewLabelWithStyle("Exports keep this format.", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
+	)
+
+	generate := func(count int) ([]string, string, error) {
+		locale, ok := fakeLocaleByName(localeOptions.Selected)
+		if !ok {
+			return nil, "", fmt.Errorf("unknown locale %q", localeOptions.Selected)
+		}
+
+		// keep the columns in listFakeField order, not in click order
+		var fields []string
+		for _, field := range listFakeField {
+			for _, selected := range fieldOptions.Selected {
+				if field == selected {
+					fields = append(fields, field)
+				}
+			}
+		}
+		if len(fields) == 0 {
+			return nil, "", errors.New("choose at least one field")
+		}
+
+		rows := make([][]string, 0, count)
+		for i := 0; i < count; i++ {
+			row, err := FakeRecord(locale, fields)
+			if err != nil {
+				return nil, "", err
+			}
+			rows = append(rows, row)
+		}
+
+		out, err := FormatFakeRows(formatOptions.Selected, tableEntry.Text, fields, rows)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return out, fmt.Sprintf("Generated %d %s rows as %s", count, locale.Name, formatOptions.Selected), nil
+	}
+
+	exportAs := func() (string, int) {
+		return fakeFormatExport(formatOptions.Selected)
+	}
+
+	return panel, generate, exportAs
+}
@@ -2,8 +2,9 @@ package main
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
-
+	"io"
 	"math/big"
 	"strings"
 
@@ -21,18 +22,35 @@ func generateOne(charset string, length int) (string, error) {
 	}
 
 	var sb strings.Builder
-	max := big.NewInt(int64(len(charset)))
 	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, max)
+		n, err := randomInt(len(charset))
 		if err != nil {
 			return "", err
 		}
-		sb.WriteByte(charset[n.Int64()])
+		sb.WriteByte(charset[n])
 	}
 
 	return sb.String(), nil
 }
 
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(randomSource, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n.Int64()), nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(randomSource, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
 func buildCharset(lower, upper, digits, symbols bool) string {
 	var b strings.Builder
 	if lower {
@@ -73,50 +91,287 @@ func windowStringGenerator(w fyne.Window) func() {
 		countEntry.SetText("5")
 
 		seedLabel := widget.NewLabel("")
+		seedLabel.Wrapping = fyne.TextWrapWord
 
-		// right: result area
-		resultArea := widget.NewMultiLineEntry()
-		resultArea.SetPlaceHolder("Generated strings will appear here (one per line).")
-		resultArea.Wrapping = fyne.TextWrapWord
+		// right: result list
+		resultArea := newResultList(w)
 
 		generateBtn := widget.NewButtonWithIcon("Generate", theme.DocumentPrintIcon(), nil)
 		clearBtn := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
-			resultArea.SetText("")
+			resultArea.Set(nil)
 		})
 
-		generate := func() {
-			// parse inputs
-			length := 0
-			count := 0
+		minEntries := map[string]*widget.Entry{}
+		minFields := map[string]fyne.CanvasObject{}
+		for _, class := range listCharClass {
+			minEntries[class] = widget.NewEntry()
+			minEntries[class].SetPlaceHolder("min")
+			minEntries[class].SetText("0")
+			minFields[class] = container.NewGridWrap(fyne.NewSize(60, minEntries[class].MinSize().Height), minEntries[class])
+		}
+
+		ambiguousCheck := widget.NewCheck("Exclude ambiguous (0 O o l 1 I |)", nil)
+		noRepeatCheck := widget.NewCheck("No repeated characters (aa)", nil)
+		noSequentialCheck := widget.NewCheck("No sequential characters (abc, 321)", nil)
 
+		excludeEntry := widget.NewEntry()
+		excludeEntry.SetPlaceHolder("Characters to exclude")
+
+		customEntry := widget.NewEntry()
+		customEntry.SetPlaceHolder("Custom charset, replaces the classes above")
+
+		policy := func() (CharsetPolicy, error) {
+			p := CharsetPolicy{
+				Lower:            lowerCheck.Checked,
+				Upper:            upperCheck.Checked,
+				Digits:           digitCheck.Checked,
+				Symbols:          symbolCheck.Checked,
+				Min:              map[string]int{},
+				ExcludeAmbiguous: ambiguousCheck.Checked,
+				Exclude:          excludeEntry.Text,
+				Custom:           customEntry.Text,
+				NoRepeat:         noRepeatCheck.Checked,
+				NoSequential:     noSequentialCheck.Checked,
+			}
+
+			for _, class := range listCharClass {
+				var min int
+				text := minEntries[class].Text
+				if _, err := fmt.Sscan(text, &min); err != nil || min < 0 {
+					return p, fmt.Errorf("invalid min: %v", text)
+				}
+				p.Min[class] = min
+			}
+
+			return p, nil
+		}
+
+		entropyLabel := widget.NewLabel("")
+		updateEntropy := func() {
+			length := 0
 			_, err := fmt.Sscan(lengthEntry.Text, &length)
 			if err != nil || length <= 0 {
-				dialog.ShowError(fmt.Errorf("invalid length: %v", lengthEntry.Text), w)
+				entropyLabel.SetText("")
 				return
 			}
-			_, err = fmt.Sscan(countEntry.Text, &count)
-			if err != nil || count <= 0 {
-				dialog.ShowError(fmt.Errorf("invalid count: %v", countEntry.Text), w)
-				return
+
+			// the repeat, sequence and minimum rules take away a little, so this is an upper bound
+			p, _ := policy()
+			charset := p.Charset()
+			entropyLabel.SetText(fmt.Sprintf("Entropy: ≤ %.1f bits (%d chars charset)", charsetEntropy(len(charset), length), len(charset)))
+		}
+
+		for _, check := range []*widget.Check{lowerCheck, upperCheck, digitCheck, symbolCheck, ambiguousCheck, noRepeatCheck, noSequentialCheck} {
+			check.OnChanged = func(bool) { updateEntropy() }
+		}
+		for _, entry := range []*widget.Entry{lengthEntry, excludeEntry, customEntry, minEntries["lower"], minEntries["upper"], minEntries["digit"], minEntries["symbol"]} {
+			entry.OnChanged = func(string) { updateEntropy() }
+		}
+		updateEntropy()
+
+		randomOptions := container.NewVBox(
+			widget.NewLabelWithStyle("Charset", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			container.NewBorder(nil, nil, nil, minFields["lower"], lowerCheck),
+			container.NewBorder(nil, nil, nil, minFields["upper"], upperCheck),
+			container.NewBorder(nil, nil, nil, minFields["digit"], digitCheck),
+			container.NewBorder(nil, nil, nil, minFields["symbol"], symbolCheck),
+			customEntry,
+			excludeEntry,
+			ambiguousCheck,
+			noRepeatCheck,
+			noSequentialCheck,
+			container.NewGridWithColumns(2, widget.NewLabel("Length:"), lengthEntry),
+			entropyLabel,
+		)
+
+		generateRandom := func(count int) ([]string, string, error) {
+			length := 0
+			_, err := fmt.Sscan(lengthEntry.Text, &length)
+			if err != nil || length <= 0 {
+				return nil, "", fmt.Errorf("invalid length: %v", lengthEntry.Text)
 			}
 
-			charset := buildCharset(lowerCheck.Checked, upperCheck.Checked, digitCheck.Checked, symbolCheck.Checked)
-			if charset == "" {
-				dialog.ShowInformation("No charset", "Please choose at least one charset option.", w)
-				return
+			p, err := policy()
+			if err != nil {
+				return nil, "", err
+			}
+			if err := p.Validate(length); err != nil {
+				return nil, "", err
 			}
 
 			var out []string
 			for i := 0; i < count; i++ {
-				s, err := generateOne(charset, length)
+				s, err := GenerateWithPolicy(p, length)
 				if err != nil {
-					dialog.ShowError(err, w)
-					return
+					return nil, "", err
 				}
 				out = append(out, s)
 			}
-			resultArea.SetText(strings.Join(out, "\n"))
-			seedLabel.SetText(fmt.Sprintf("Generated %d strings, %d chars each", count, length))
+
+			return out, fmt.Sprintf("Generated %d strings, %d chars each, ≤ %.1f bits of entropy", count, length, charsetEntropy(len(p.Charset()), length)), nil
+		}
+
+		passphraseOptions, generatePassphrases := passphraseModePanel(w)
+		idOptions, generateIDs := idModePanel(w)
+		templateOptions, generateTemplates := templateModePanel(w)
+		fakeDataOptions, generateFakeData, fakeDataExport := fakeDataModePanel(w)
+		otpOptions, generateOTPSecrets := otpModePanel(w)
+
+		modePanels := map[string]fyne.CanvasObject{
+			"Random String": randomOptions,
+			"Passphrase":    passphraseOptions,
+			"Identifier":    idOptions,
+			"Template":      templateOptions,
+			"Fake Data":     fakeDataOptions,
+			"OTP Secret":    otpOptions,
+		}
+		modeGenerators := map[string]func(count int) ([]string, string, error){
+			"Random String": generateRandom,
+			"Passphrase":    generatePassphrases,
+			"Identifier":    generateIDs,
+			"Template":      generateTemplates,
+			"Fake Data":     generateFakeData,
+			"OTP Secret":    generateOTPSecrets,
+		}
+
+		modeStack := container.NewStack(randomOptions)
+		modeOptions := widget.NewSelect([]string{"Random String", "Passphrase", "Identifier", "Template", "Fake Data", "OTP Secret"}, func(mode string) {
+			modeStack.Objects = []fyne.CanvasObject{modePanels[mode]}
+			modeStack.Refresh()
+		})
+		modeOptions.SetSelected("Random String")
+
+		parseCount := func() (int, error) {
+			count := 0
+
+			_, err := fmt.Sscan(countEntry.Text, &count)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid count: %v", countEntry.Text)
+			}
+
+			return count, nil
+		}
+
+		// deterministic mode: same seed, same options, same batch
+		seedEntry := widget.NewEntry()
+		seedEntry.SetPlaceHolder("Seed")
+		seedEntry.Disable()
+
+		newSeedBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+			seed, err := newSeed()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			seedEntry.SetText(seed)
+		})
+		newSeedBtn.Disable()
+
+		seededCheck := widget.NewCheck("Deterministic (seeded, not for secrets)", func(checked bool) {
+			if !checked {
+				seedEntry.Disable()
+				newSeedBtn.Disable()
+				return
+			}
+
+			seedEntry.Enable()
+			newSeedBtn.Enable()
+			if seedEntry.Text == "" {
+				newSeedBtn.OnTapped()
+			}
+		})
+
+		// OTP secrets always read crypto/rand, a seed can't make their batch repeat
+		unseededModes := map[string]bool{"OTP Secret": true}
+		seeded := func() bool {
+			return seededCheck.Checked && !unseededModes[modeOptions.Selected]
+		}
+		modeChanged := modeOptions.OnChanged
+		modeOptions.OnChanged = func(mode string) {
+			modeChanged(mode)
+			if unseededModes[mode] {
+				seededCheck.Disable()
+			} else {
+				seededCheck.Enable()
+			}
+		}
+
+		usedSeed := ""
+		usedSeedLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+		usedSeedLabel.Truncation = fyne.TextTruncateEllipsis
+		copySeedBtn := widget.NewButtonWithIcon("Copy Seed", theme.ContentCopyIcon(), func() {
+			w.Clipboard().SetContent(usedSeed)
+		})
+		seedRow := container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), copySeedBtn, usedSeedLabel)
+		seedRow.Hide()
+
+		// currentGenerator returns the selected mode's generator, drawing from a
+		// ChaCha8 stream keyed by the seed when deterministic mode is on. The
+		// stream carries on across calls, so batched exports don't repeat.
+		currentGenerator := func() (func(count int) ([]string, string, error), error) {
+			modeGenerate := modeGenerators[modeOptions.Selected]
+			if !seeded() {
+				return modeGenerate, nil
+			}
+
+			seed := seedEntry.Text
+			if seed == "" {
+				return nil, errors.New("seed must be fill in deterministic mode")
+			}
+
+			src, clock := seededSource(seed), seededClock(seed)
+			return func(count int) ([]string, string, error) {
+				restore := useRandomSource(src, clock)
+				defer restore()
+
+				out, info, err := modeGenerate(count)
+				return out, fmt.Sprintf("Deterministic, seed %q. %s", seed, info), err
+			}, nil
+		}
+
+		// modePassthrough reports whether the selected mode is exported in its own format
+		modePassthrough := func() (string, int, bool) {
+			if modeOptions.Selected != "Fake Data" {
+				return "", 0, false
+			}
+			extension, headerLines := fakeDataExport()
+			return extension, headerLines, true
+		}
+
+		// how the shown results are exported, recorded when they are generated
+		shownExtension, shownHeaderLines, shownPassthrough := "", 0, false
+
+		generate := func() {
+			count, err := parseCount()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			modeGenerate, err := currentGenerator()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			out, info, err := modeGenerate(count)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			resultArea.Set(out)
+			shownExtension, shownHeaderLines, shownPassthrough = modePassthrough()
+			seedLabel.SetText(info)
+
+			if seeded() {
+				usedSeed = seedEntry.Text
+				usedSeedLabel.SetText("Seed: " + usedSeed + " (deterministic, not for secrets)")
+				seedRow.Show()
+			} else {
+				usedSeed = ""
+				seedRow.Hide()
+			}
 			dialog.ShowInformation("Generate", "String has been Generated ✔", w)
 		}
 
@@ -130,36 +385,67 @@ func windowStringGenerator(w fyne.Window) func() {
 		}
 
 		left := container.NewVBox(
-			widget.NewLabelWithStyle("Charset", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-			lowerCheck,
-			upperCheck,
-			digitCheck,
-			symbolCheck,
+			widget.NewLabelWithStyle("Mode", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			modeOptions,
+			vPadding(10),
+			widget.NewSeparator(),
+			modeStack,
 			vPadding(10),
 			widget.NewSeparator(),
 			widget.NewLabelWithStyle("Options", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-			container.NewGridWithColumns(3,
-				widget.NewLabel("Length:"), lengthEntry, widget.NewLabel(""),
-				widget.NewLabel("Count:"), countEntry, widget.NewLabel(""),
+			container.NewGridWithColumns(2,
+				widget.NewLabel("Count:"), countEntry,
 			),
+			seededCheck,
+			container.NewBorder(nil, nil, nil, newSeedBtn, seedEntry),
 			vPadding(10),
 			widget.NewSeparator(),
 			vPadding(10),
 			container.NewHBox(generateBtn, clearBtn),
 			seedLabel,
+			vPadding(10),
+			widget.NewSeparator(),
+			exportPanel(w, resultArea.Values, parseCount, currentGenerator, modePassthrough, func() (string, int, bool) {
+				return shownExtension, shownHeaderLines, shownPassthrough
+			}),
 		)
 
+		strengthPanel, checkStrength := strengthCheckerPanel()
+
+		checkBtn := widget.NewButtonWithIcon("Check Strength", theme.SearchIcon(), func() {
+			// check the selected line, or the first generated one
+			checkStrength(resultArea.Selected())
+		})
+
 		resultLabel := widget.NewLabelWithStyle("Result", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-		right := container.NewBorder(resultLabel, nil, nil, nil, container.NewScroll(resultArea))
+		results := container.NewBorder(container.NewBorder(nil, nil, resultLabel, container.NewHBox(resultArea.Actions, checkBtn)), seedRow, nil, nil, resultArea.Content)
+
+		hashTab := hashPanel(w, func() string {
+			return strings.Join(resultArea.Values(), "\n")
+		})
+
+		authenticator, lockVault := authenticatorPanel(w, resultArea.Selected)
+
+		tools := container.NewAppTabs(
+			container.NewTabItemWithIcon("Strength", theme.VisibilityIcon(), strengthPanel),
+			container.NewTabItemWithIcon("Hash", theme.ConfirmIcon(), hashTab),
+			container.NewTabItemWithIcon("Authenticator", theme.AccountIcon(), authenticator),
+		)
+
+		right := container.NewVSplit(results, padding(5, tools))
+		right.SetOffset(0.6)
 
 		rightPanel := padding(10, right)
-		leftPanel := padding(15, left)
+		leftPanel := padding(15, container.NewVScroll(left))
 
 		split := container.NewHSplit(leftPanel, rightPanel)
-		split.SetOffset(0.2)
+		split.SetOffset(0.3)
 
-		w.SetContent(
+		setToolContent(w,
 			container.NewBorder(toolbars(w), nil, nil, nil, split),
 		)
+
+		// the vault doesn't stay unlocked once the tool is left or sent to the tray
+		onToolLeave, onToolHide = lockVault, lockVault
 	}
 }
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// hashPanel hashes its input, results returns the generator output for the "Use Results" button.
+func hashPanel(w fyne.Window, results func() string) fyne.CanvasObject {
+	inputArea := widget.NewMultiLineEntry()
+	inputArea.SetPlaceHolder("Text to hash, or press Use Results")
+
+	outputArea := widget.NewMultiLineEntry()
+	outputArea.SetPlaceHolder("Hashes will appear here")
+	outputArea.Wrapping = fyne.TextWrapBreak
+
+	perLineCheck := widget.NewCheck("Hash each line separately", nil)
+	perLineCheck.SetChecked(true)
+
+	useResultsBtn := widget.NewButtonWithIcon("Use Results", theme.ContentPasteIcon(), func() {
+		inputArea.SetText(results())
+	})
+
+	inputs := func() []string {
+		if perLineCheck.Checked {
+			var lines []string
+			for _, line := range strings.Split(inputArea.Text, "\n") {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			return lines
+		}
+
+		return []string{inputArea.Text}
+	}
+
+	// digests and HMACs
+	algorithmOptions := widget.NewSelect(listHashAlgorithm, nil)
+	algorithmOptions.SetSelected("SHA-256")
+
+	encodingOptions := widget.NewRadioGroup(listDigestEncoding, nil)
+	encodingOptions.Horizontal = true
+	encodingOptions.SetSelected("Hex")
+
+	hmacKeyEntry := widget.NewPasswordEntry()
+	hmacKeyEntry.SetPlaceHolder("Optional, computes an HMAC")
+
+	digestBtn := widget.NewButtonWithIcon("Digest", theme.ConfirmIcon(), func() {
+		var out []string
+		for _, input := range inputs() {
+			sum, err := Digest(algorithmOptions.Selected, []byte(input), []byte(hmacKeyEntry.Text), encodingOptions.Selected)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			out = append(out, sum)
+		}
+
+		outputArea.SetText(strings.Join(out, "\n"))
+	})
+
+	digestForm := widget.NewForm()
+	digestForm.Append("Algorithm:", algorithmOptions)
+	digestForm.Append("Encoding:", encodingOptions)
+	digestForm.Append("HMAC key:", hmacKeyEntry)
+
+	// password hashes
+	params := defaultPasswordHashParams
+
+	bcryptCostEntry := widget.NewEntry()
+	bcryptCostEntry.SetText(fmt.Sprint(params.BcryptCost))
+
+	scryptLogNEntry := widget.NewEntry()
+	scryptLogNEntry.SetText(fmt.Sprint(params.ScryptLogN))
+	scryptREntry := widget.NewEntry()
+	scryptREntry.SetText(fmt.Sprint(params.ScryptR))
+	scryptPEntry := widget.NewEntry()
+	scryptPEntry.SetText(fmt.Sprint(params.ScryptP))
+
+	argonTimeEntry := widget.NewEntry()
+	argonTimeEntry.SetText(fmt.Sprint(params.ArgonTime))
+	argonMemoryEntry := widget.NewEntry()
+	argonMemoryEntry.SetText(fmt.Sprint(params.ArgonMemory))
+	argonThreadsEntry := widget.NewEntry()
+	argonThreadsEntry.SetText(fmt.Sprint(params.ArgonThreads))
+
+	bcryptForm := widget.NewForm(widget.NewFormItem("Cost:", bcryptCostEntry))
+	scryptForm := widget.NewForm(
+		widget.NewFormItem("log2(N):", scryptLogNEntry),
+		widget.NewFormItem("r:", scryptREntry),
+		widget.NewFormItem("p:", scryptPEntry),
+	)
+	argonForm := widget.NewForm(
+		widget.NewFormItem("Time:", argonTimeEntry),
+		widget.NewFormItem("Memory (KiB):", argonMemoryEntry),
+		widget.NewFormItem("Threads:", argonThreadsEntry),
+	)
+
+	passwordHashOptions := widget.NewSelect(listPasswordHash, func(algorithm string) {
+		bcryptForm.Hide()
+		scryptForm.Hide()
+		argonForm.Hide()
+
+		switch algorithm {
+		case "bcrypt":
+			bcryptForm.Show()
+		case "scrypt":
+			scryptForm.Show()
+		case "argon2id":
+			argonForm.Show()
+		}
+	})
+	passwordHashOptions.SetSelected("argon2id")
+
+	type paramField struct {
+		entry *widget.Entry
+		name  string
+		value any
+	}
+
+	// readParams parses only the fields of algorithm, the hidden forms keep their defaults
+	readParams := func(algorithm string) (PasswordHashParams, error) {
+		p := params
+
+		threads := uint(p.ArgonThreads)
+		fields := map[string][]paramField{
+			"bcrypt": {
+				{bcryptCostEntry, "bcrypt cost", &p.BcryptCost},
+			},
+			"scrypt": {
+				{scryptLogNEntry, "scrypt log2(N)", &p.ScryptLogN},
+				{scryptREntry, "scrypt r", &p.ScryptR},
+				{scryptPEntry, "scrypt p", &p.ScryptP},
+			},
+			"argon2id": {
+				{argonTimeEntry, "argon2id time", &p.ArgonTime},
+				{argonMemoryEntry, "argon2id memory", &p.ArgonMemory},
+				{argonThreadsEntry, "argon2id threads", &threads},
+			},
+		}
+		for _, field := range fields[algorithm] {
+			if _, err := fmt.Sscan(field.entry.Text, field.value); err != nil {
+				return p, fmt.Errorf("invalid %s: %v", field.name, field.entry.Text)
+			}
+		}
+		if threads > 255 {
+			return p, fmt.Errorf("invalid argon2id threads: %d", threads)
+		}
+		p.ArgonThreads = uint8(threads)
+
+		return p, nil
+	}
+
+	var passwordHashBtn *widget.Button
+	passwordHashBtn = widget.NewButtonWithIcon("Hash Passwords", theme.ConfirmIcon(), func() {
+		algorithm := passwordHashOptions.Selected
+		p, err := readParams(algorithm)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		passwords := inputs()
+
+		// high costs take seconds per password, keep the UI responsive
+		passwordHashBtn.Disable()
+		outputArea.SetText(fmt.Sprintf("Hashing %d passwords with %s…", len(passwords), algorithm))
+
+		go func() {
+			var out []string
+			var hashErr error
+			for _, password := range passwords {
+				encoded, err := HashPassword(algorithm, password, p)
+				if err != nil {
+					hashErr = err
+					break
+				}
+				out = append(out, encoded)
+			}
+
+			fyne.Do(func() {
+				passwordHashBtn.Enable()
+				if hashErr != nil {
+					outputArea.SetText("")
+					dialog.ShowError(hashErr, w)
+					return
+				}
+
+				outputArea.SetText(strings.Join(out, "\n"))
+			})
+		}()
+	})
+
+	// verification
+	verifyPasswordEntry := widget.NewPasswordEntry()
+	verifyPasswordEntry.SetPlaceHolder("Password")
+
+	verifyHashEntry := widget.NewEntry()
+	verifyHashEntry.SetPlaceHolder("$argon2id$…, $2b$… or $scrypt$…")
+
+	verifyStack := container.NewStack()
+
+	verifyBtn := widget.NewButtonWithIcon("Verify", theme.SearchIcon(), func() {
+		verifyStack.Objects = []fyne.CanvasObject{statusText("Verifying…", theme.ColorNameDisabled)}
+		verifyStack.Refresh()
+
+		password, encoded := verifyPasswordEntry.Text, verifyHashEntry.Text
+		go func() {
+			algorithm, ok, err := VerifyPassword(password, encoded)
+
+			fyne.Do(func() {
+				var result fyne.CanvasObject
+				switch {
+				case err != nil:
+					result = statusText("✘ "+err.Error(), theme.ColorNameError)
+				case ok:
+					result = statusText("✔ Password matches the "+algorithm+" hash", theme.ColorNameSuccess)
+				default:
+					result = statusText("✘ Password does not match the "+algorithm+" hash", theme.ColorNameError)
+				}
+
+				verifyStack.Objects = []fyne.CanvasObject{result}
+				verifyStack.Refresh()
+			})
+		}()
+	})
+
+	options := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabelWithStyle("Input", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), useResultsBtn),
+		perLineCheck,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Digest / HMAC", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		digestForm,
+		container.NewHBox(digestBtn),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Password Hash", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		passwordHashOptions,
+		bcryptForm,
+		scryptForm,
+		argonForm,
+		container.NewHBox(passwordHashBtn),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Verify Password", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		verifyPasswordEntry,
+		verifyHashEntry,
+		container.NewBorder(nil, nil, verifyBtn, nil, verifyStack),
+	)
+
+	texts := container.NewVSplit(inputArea, outputArea)
+
+	split := container.NewHSplit(container.NewVScroll(options), texts)
+	split.SetOffset(0.45)
+
+	return split
+}
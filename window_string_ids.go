@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+var snowflake = &SnowflakeGenerator{Epoch: defaultSnowflakeEpoch, Machine: 1}
+
+func idModePanel(w fyne.Window) (fyne.CanvasObject, func(count int) ([]string, string, error)) {
+	nanoAlphabetEntry := widget.NewEntry()
+	nanoAlphabetEntry.SetText(nanoIDAlphabet)
+
+	nanoSizeEntry := widget.NewEntry()
+	nanoSizeEntry.SetText("21")
+
+	machineEntry := widget.NewEntry()
+	machineEntry.SetText(fmt.Sprint(snowflake.Machine))
+
+	epochEntry := widget.NewEntry()
+	epochEntry.SetPlaceHolder("Epoch in unix milliseconds")
+	epochEntry.SetText(fmt.Sprint(snowflake.Epoch))
+
+	nanoOptions := widget.NewForm(
+		widget.NewFormItem("Alphabet:", nanoAlphabetEntry),
+		widget.NewFormItem("Size:", nanoSizeEntry),
+	)
+	snowflakeOptions := widget.NewForm(
+		widget.NewFormItem("Machine ID:", machineEntry),
+		widget.NewFormItem("Epoch (ms):", epochEntry),
+	)
+
+	typeOptions := widget.NewSelect(listIDType, func(kind string) {
+		nanoOptions.Hide()
+		snowflakeOptions.Hide()
+
+		switch kind {
+		case "NanoID":
+			nanoOptions.Show()
+		case "Snowflake":
+			snowflakeOptions.Show()
+		}
+	})
+	typeOptions.SetSelected("UUID v4")
+
+	decodeEntry := widget.NewEntry()
+	decodeEntry.SetPlaceHolder("Paste a UUID, ULID, KSUID or Snowflake")
+
+	decodeResult := widget.NewLabel("")
+	decodeResult.Wrapping = fyne.TextWrapWord
+
+	parseEpoch := func() (int64, error) {
+		var epoch int64
+		_, err := fmt.Sscan(epochEntry.Text, &epoch)
+		if err != nil || epoch < 0 {
+			return 0, fmt.Errorf("invalid snowflake epoch: %v", epochEntry.Text)
+		}
+
+		return epoch, nil
+	}
+
+	decodeEntry.OnChanged = func(id string) {
+		if strings.TrimSpace(id) == "" {
+			decodeResult.SetText("")
+			return
+		}
+
+		epoch, err := parseEpoch()
+		if err != nil {
+			decodeResult.SetText(err.Error())
+			return
+		}
+
+		lines, err := DecodeID(id, epoch)
+		if err != nil {
+			decodeResult.SetText(err.Error())
+			return
+		}
+
+		decodeResult.SetText(strings.Join(lines, "\n"))
+	}
+
+	panel := container.NewVBox(
+		widget.NewLabelWithStyle("Identifier", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		typeOptions,
+		nanoOptions,
+		snowflakeOptions,
+		vPadding(10),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Decode Timestamp", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		decodeEntry,
+		decodeResult,
+	)
+
+	generate := func(count int) ([]string, string, error) {
+		kind := typeOptions.Selected
+
+		var nanoSize int
+		if kind == "NanoID" {
+			_, err := fmt.Sscan(nanoSizeEntry.Text, &nanoSize)
+			if err != nil || nanoSize <= 0 {
+				return nil, "", fmt.Errorf("invalid NanoID size: %v", nanoSizeEntry.Text)
+			}
+		}
+
+		if kind == "Snowflake" {
+			epoch, err := parseEpoch()
+			if err != nil {
+				return nil, "", err
+			}
+
+			var machine int64
+			_, err = fmt.Sscan(machineEntry.Text, &machine)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid machine ID: %v", machineEntry.Text)
+			}
+
+			snowflake.Epoch = epoch
+			snowflake.Machine = machine
+		}
+
+		// a seeded batch starts from a fresh sequence, not the state left by earlier batches
+		generator := snowflake
+		if isSeeded() {
+			generator = &SnowflakeGenerator{Epoch: snowflake.Epoch, Machine: snowflake.Machine}
+		}
+
+		var out []string
+		for i := 0; i < count; i++ {
+			id, err := generateID(kind, randomClock(), nanoAlphabetEntry.Text, nanoSize, generator)
+			if err != nil {
+				return nil, "", err
+			}
+			out = append(out, id)
+		}
+
+		return out, fmt.Sprintf("Generated %d %s", count, kind), nil
+	}
+
+	return panel, generate
+}
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// otpRefresh redraws the live codes of the authenticator on screen. One
+// ticker serves every panel, the newest one replaces the callback.
+var otpRefresh func()
+var otpTickerOnce sync.Once
+
+func startOTPTicker() {
+	otpTickerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			for range ticker.C {
+				fyne.Do(func() {
+					if otpRefresh != nil {
+						otpRefresh()
+					}
+				})
+			}
+		}()
+	})
+}
+
+// otpOptionsForm holds the type, algorithm, digits and period shared by the
+// generator mode and the authenticator.
+type otpOptionsForm struct {
+	Type      *widget.RadioGroup
+	Algorithm *widget.Select
+	Digits    *widget.RadioGroup
+	Period    *widget.Entry
+	Counter   *widget.Entry
+}
+
+func newOTPOptionsForm() *otpOptionsForm {
+	f := &otpOptionsForm{
+		Algorithm: widget.NewSelect(listOTPAlgorithm, nil),
+		Digits:    widget.NewRadioGroup([]string{"6", "8"}, nil),
+		Period:    widget.NewEntry(),
+		Counter:   widget.NewEntry(),
+	}
+
+	f.Type = widget.NewRadioGroup(listOTPType, func(kind string) {
+		if kind == "HOTP" {
+			f.Period.Disable()
+			f.Counter.Enable()
+		} else {
+			f.Period.Enable()
+			f.Counter.Disable()
+		}
+	})
+	f.Type.Horizontal = true
+	f.Digits.Horizontal = true
+
+	f.Type.SetSelected("TOTP")
+	f.Algorithm.SetSelected("SHA1")
+	f.Digits.SetSelected("6")
+	f.Period.SetText("30")
+	f.Counter.SetText("0")
+
+	return f
+}
+
+func (f *otpOptionsForm) Items() []*widget.FormItem {
+	return []*widget.FormItem{
+		widget.NewFormItem("Type:", f.Type),
+		widget.NewFormItem("Algorithm:", f.Algorithm),
+		widget.NewFormItem("Digits:", f.Digits),
+		widget.NewFormItem("Period (s):", f.Period),
+		widget.NewFormItem("Counter:", f.Counter),
+	}
+}
+
+func (f *otpOptionsForm) Account(issuer, account, secret string) (OTPAccount, error) {
+	a := OTPAccount{
+		Type:      f.Type.Selected,
+		Issuer:    issuer,
+		Account:   account,
+		Secret:    secret,
+		Algorithm: f.Algorithm.Selected,
+	}
+
+	fmt.Sscan(f.Digits.Selected, &a.Digits)
+	if a.Type == "TOTP" {
+		if _, err := fmt.Sscan(f.Period.Text, &a.Period); err != nil {
+			return a, fmt.Errorf("invalid period: %v", f.Period.Text)
+		}
+	} else if _, err := fmt.Sscan(f.Counter.Text, &a.Counter); err != nil {
+		return a, fmt.Errorf("invalid counter: %v", f.Counter.Text)
+	}
+
+	return a, a.Validate()
+}
+
+func (f *otpOptionsForm) Set(a OTPAccount) {
+	f.Type.SetSelected(a.Type)
+	f.Algorithm.SetSelected(a.Algorithm)
+	f.Digits.SetSelected(fmt.Sprint(a.Digits))
+	f.Period.SetText(fmt.Sprint(a.Period))
+	f.Counter.SetText(fmt.Sprint(a.Counter))
+}
+
+func otpModePanel(w fyne.Window) (fyne.CanvasObject, func(count int) ([]string, string, error)) {
+	sizeOptions := widget.NewSelect([]string{"10", "20", "32"}, nil)
+	sizeOptions.SetSelected("20")
+
+	outputOptions := widget.NewRadioGroup([]string{"Secret", "otpauth URI"}, nil)
+	outputOptions.Horizontal = true
+	outputOptions.SetSelected("Secret")
+
+	issuerEntry := widget.NewEntry()
+	issuerEntry.SetPlaceHolder("e.g. Sebari")
+
+	accountEntry := widget.NewEntry()
+	accountEntry.SetPlaceHolder("e.g. deploy-bot")
+
+	options := newOTPOptionsForm()
+
+	configs := widget.NewForm(
+		widget.NewFormItem("Secret bytes:", sizeOptions),
+		widget.NewFormItem("Output:", outputOptions),
+		widget.NewFormItem("Issuer:", issuerEntry),
+		widget.NewFormItem("Account:", accountEntry),
+	)
+	for _, item := range options.Items() {
+		configs.AppendItem(item)
+	}
+
+	hint := widget.NewLabel("Select a generated line and open the Authenticator tab to see its QR code and save it.")
+	hint.Wrapping = fyne.TextWrapWord
+
+	panel := container.NewVBox(
+		widget.NewLabelWithStyle("OTP Secret", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		hint,
+	)
+
+	generate := func(count int) ([]string, string, error) {
+		var size int
+		fmt.Sscan(sizeOptions.Selected, &size)
+
+		var out []string
+		for i := 0; i < count; i++ {
+			secret, err := newOTPSecret(size)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if outputOptions.Selected == "Secret" {
+				out = append(out, secret)
+				continue
+			}
+
+			account := accountEntry.Text
+			if account == "" {
+				return nil, "", errors.New("account must be fill for otpauth URIs")
+			}
+			if count > 1 {
+				account = fmt.Sprintf("%s-%d", account, i+1)
+			}
+
+			a, err := options.Account(issuerEntry.Text, account, secret)
+			if err != nil {
+				return nil, "", err
+			}
+			out = append(out, a.URI())
+		}
+
+		return out, fmt.Sprintf("Generated %d base32 secrets, %d bits each", count, size*8), nil
+	}
+
+	return panel, generate
+}
+
+// authenticatorPanel keeps OTP accounts in the encrypted vault and shows their
+// live codes, selected returns the generator line to import. The returned lock
+// drops the decrypted accounts and the master password.
+func authenticatorPanel(w fyne.Window, selected func() string) (fyne.CanvasObject, func()) {
+	var accounts []OTPAccount
+	masterPassword := ""
+
+	startOTPTicker()
+
+	// locked view
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Master password")
+
+	// unlocked view
+	issuerEntry := widget.NewEntry()
+	issuerEntry.SetPlaceHolder("Issuer")
+	accountEntry := widget.NewEntry()
+	accountEntry.SetPlaceHolder("Account")
+	secretEntry := widget.NewPasswordEntry()
+	secretEntry.SetPlaceHolder("Base32 secret")
+
+	options := newOTPOptionsForm()
+
+	qrImage := canvas.NewImageFromResource(theme.BrokenImageIcon())
+	qrImage.FillMode = canvas.ImageFillContain
+	qrImage.SetMinSize(fyne.NewSize(180, 180))
+
+	uriLabel := widget.NewLabel("")
+	uriLabel.Wrapping = fyne.TextWrapBreak
+
+	current := func() (OTPAccount, error) {
+		if accountEntry.Text == "" {
+			return OTPAccount{}, errors.New("account must be fill")
+		}
+
+		return options.Account(issuerEntry.Text, accountEntry.Text, secretEntry.Text)
+	}
+
+	showQR := func() {
+		a, err := current()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		opts := defaultQROptions
+		opts.ModuleWidth, opts.Border = 8, 2
+
+		data, err := generateQR(a.URI(), opts)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		qr := canvas.NewImageFromReader(bytes.NewReader(data), "otpauth.png")
+		qrImage.Resource = qr.Resource
+		qrImage.Refresh()
+		uriLabel.SetText(a.URI())
+	}
+
+	useSelectedBtn := widget.NewButtonWithIcon("Use Selected", theme.ContentPasteIcon(), func() {
+		line := selected()
+		if line == "" {
+			dialog.ShowError(errors.New("nothing selected, generate an OTP secret first"), w)
+			return
+		}
+
+		a, err := parseOTPAuthURI(line)
+		if err != nil {
+			// a bare secret keeps the other fields as they are
+			if _, secretErr := decodeOTPSecret(line); secretErr != nil {
+				dialog.ShowError(fmt.Errorf("selected line is neither an otpauth URI nor a base32 secret"), w)
+				return
+			}
+			secretEntry.SetText(line)
+			return
+		}
+
+		issuerEntry.SetText(a.Issuer)
+		accountEntry.SetText(a.Account)
+		secretEntry.SetText(a.Secret)
+		options.Set(a)
+	})
+
+	list := widget.NewList(
+		func() int {
+			return len(accounts)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			code := widget.NewLabelWithStyle("", fyne.TextAlignTrailing, fyne.TextStyle{Monospace: true, Bold: true})
+			ttl := widget.NewLabel("")
+
+			copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil)
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+
+			return container.NewBorder(nil, nil, nil, container.NewHBox(code, ttl, copyBtn, deleteBtn), name)
+		},
+		nil,
+	)
+
+	save := func() {
+		if err := saveOTPVault(masterPassword, accounts); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}
+
+	list.UpdateItem = func(i widget.ListItemID, o fyne.CanvasObject) {
+		row := o.(*fyne.Container)
+		name := row.Objects[0].(*widget.Label)
+		actions := row.Objects[1].(*fyne.Container)
+		code := actions.Objects[0].(*widget.Label)
+		ttl := actions.Objects[1].(*widget.Label)
+		copyBtn := actions.Objects[2].(*widget.Button)
+		deleteBtn := actions.Objects[3].(*widget.Button)
+
+		a := accounts[i]
+		name.SetText(a.Label())
+
+		value, remaining, err := a.Code(time.Now())
+		if err != nil {
+			code.SetText("error")
+			ttl.SetText("")
+		} else {
+			code.SetText(value)
+			if a.Type == "HOTP" {
+				ttl.SetText(fmt.Sprintf("#%d", a.Counter))
+			} else {
+				ttl.SetText(fmt.Sprintf("%2ds", int(remaining.Seconds())))
+			}
+		}
+
+		copyBtn.OnTapped = func() {
+			copySecret(w, value)
+
+			// an HOTP code is spent once used, move on to the next counter
+			if a.Type == "HOTP" {
+				accounts[i].Counter++
+				save()
+				list.RefreshItem(i)
+			}
+		}
+		deleteBtn.OnTapped = func() {
+			dialog.ShowConfirm("Delete", fmt.Sprintf("Remove %s from the vault?", a.Label()), func(ok bool) {
+				if !ok {
+					return
+				}
+
+				accounts = append(accounts[:i], accounts[i+1:]...)
+				save()
+				list.Refresh()
+			}, w)
+		}
+	}
+
+	addBtn := widget.NewButtonWithIcon("Save to Vault", theme.DocumentSaveIcon(), func() {
+		a, err := current()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		accounts = append(accounts, a)
+		save()
+		list.Refresh()
+		secretEntry.SetText("")
+	})
+
+	previewBtn := widget.NewButtonWithIcon("Show QR", theme.ViewFullScreenIcon(), showQR)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Issuer:", issuerEntry),
+		widget.NewFormItem("Account:", accountEntry),
+		widget.NewFormItem("Secret:", secretEntry),
+	)
+	for _, item := range options.Items() {
+		form.AppendItem(item)
+	}
+
+	content := container.NewStack()
+
+	var lockedView, unlockedView fyne.CanvasObject
+
+	lock := func() {
+		accounts = nil
+		masterPassword = ""
+		otpRefresh = nil
+		content.Objects = []fyne.CanvasObject{lockedView}
+		content.Refresh()
+	}
+	lockBtn := widget.NewButtonWithIcon("Lock", theme.LogoutIcon(), lock)
+
+	unlock := func() {
+		password := passwordEntry.Text
+		if password == "" {
+			dialog.ShowError(errors.New("master password must be fill"), w)
+			return
+		}
+
+		loaded, err := loadOTPVault(password)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		accounts = loaded
+		masterPassword = password
+		passwordEntry.SetText("")
+		otpRefresh = list.Refresh
+		list.Refresh()
+
+		content.Objects = []fyne.CanvasObject{unlockedView}
+		content.Refresh()
+	}
+	passwordEntry.OnSubmitted = func(string) {
+		unlock()
+	}
+
+	lockedInfo := widget.NewLabel("Secrets are stored encrypted (AES-256-GCM, argon2id key). A new vault is created with the first password you enter, it can not be recovered if forgotten.")
+	lockedInfo.Wrapping = fyne.TextWrapWord
+
+	lockedView = container.NewVBox(
+		widget.NewLabelWithStyle("Authenticator", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		lockedInfo,
+		passwordEntry,
+		container.NewHBox(widget.NewButtonWithIcon("Unlock", theme.LoginIcon(), unlock)),
+	)
+
+	editor := container.NewVBox(
+		container.NewHBox(useSelectedBtn, previewBtn, addBtn),
+		form,
+		container.NewCenter(qrImage),
+		uriLabel,
+	)
+
+	codes := container.NewBorder(
+		container.NewBorder(nil, nil, widget.NewLabelWithStyle("Live Codes", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), lockBtn),
+		nil, nil, nil,
+		list,
+	)
+
+	split := container.NewHSplit(container.NewVScroll(editor), codes)
+	split.SetOffset(0.5)
+	unlockedView = split
+
+	content.Objects = []fyne.CanvasObject{lockedView}
+
+	return content, lock
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// customWordlists outlives the panel, so a reopened window still offers
+// every list loaded before. customWordlistNames keeps them in load order.
+var customWordlists = map[string][]string{}
+var customWordlistNames []string
+
+func wordlistNames() []string {
+	return append([]string{"EFF Large"}, customWordlistNames...)
+}
+
+func passphraseModePanel(w fyne.Window) (fyne.CanvasObject, func(count int) ([]string, string, error)) {
+	wordsEntry := widget.NewEntry()
+	wordsEntry.SetText("6")
+
+	separatorEntry := widget.NewEntry()
+	separatorEntry.SetText("-")
+
+	capitalOptions := widget.NewSelect(listCapitalization, nil)
+	capitalOptions.SetSelected("lowercase")
+
+	digitCheck := widget.NewCheck("Add a digit", nil)
+	symbolCheck := widget.NewCheck("Add a symbol", nil)
+
+	wordlistOptions := widget.NewSelect(wordlistNames(), nil)
+	wordlistOptions.SetSelected("EFF Large")
+
+	entropyLabel := widget.NewLabel("")
+
+	wordlist := func() []string {
+		if words, ok := customWordlists[wordlistOptions.Selected]; ok {
+			return words
+		}
+
+		return effLargeWordlist
+	}
+
+	options := func() (PassphraseOptions, error) {
+		var words int
+		_, err := fmt.Sscan(wordsEntry.Text, &words)
+		if err != nil || words <= 0 {
+			return PassphraseOptions{}, fmt.Errorf("invalid word count: %v", wordsEntry.Text)
+		}
+
+		return PassphraseOptions{
+			Words:          words,
+			Separator:      separatorEntry.Text,
+			Capitalization: capitalOptions.Selected,
+			InjectDigit:    digitCheck.Checked,
+			InjectSymbol:   symbolCheck.Checked,
+			Wordlist:       wordlist(),
+		}, nil
+	}
+
+	updateEntropy := func() {
+		opts, err := options()
+		if err != nil {
+			entropyLabel.SetText("")
+			return
+		}
+
+		entropyLabel.SetText(fmt.Sprintf("Entropy: %.1f bits (%d words in list)", passphraseEntropy(opts), len(opts.Wordlist)))
+	}
+
+	wordsEntry.OnChanged = func(string) { updateEntropy() }
+	capitalOptions.OnChanged = func(string) { updateEntropy() }
+	digitCheck.OnChanged = func(bool) { updateEntropy() }
+	symbolCheck.OnChanged = func(bool) { updateEntropy() }
+	wordlistOptions.OnChanged = func(string) { updateEntropy() }
+	updateEntropy()
+
+	loadBtn := widget.NewButtonWithIcon("Load", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			words := parseWordlist(string(data))
+			if len(words) < 2 {
+				dialog.ShowError(fmt.Errorf("%s has less than 2 words", reader.URI().Name()), w)
+				return
+			}
+
+			name := reader.URI().Name()
+			if _, ok := customWordlists[name]; !ok {
+				customWordlistNames = append(customWordlistNames, name)
+			}
+			customWordlists[name] = words
+			wordlistOptions.SetOptions(wordlistNames())
+			wordlistOptions.SetSelected(name)
+		}, w)
+	})
+
+	configs := widget.NewForm()
+	configs.Append("Words:", wordsEntry)
+	configs.Append("Separator:", separatorEntry)
+	configs.Append("Case:", capitalOptions)
+	configs.Append("Wordlist:", container.NewBorder(nil, nil, nil, loadBtn, wordlistOptions))
+
+	panel := container.NewVBox(
+		widget.NewLabelWithStyle("Passphrase", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		configs,
+		digitCheck,
+		symbolCheck,
+		entropyLabel,
+	)
+
+	generate := func(count int) ([]string, string, error) {
+		opts, err := options()
+		if err != nil {
+			return nil, "", err
+		}
+
+		var out []string
+		for i := 0; i < count; i++ {
+			s, err := generatePassphrase(opts)
+			if err != nil {
+				return nil, "", err
+			}
+			out = append(out, s)
+		}
+
+		return out, fmt.Sprintf("Generated %d passphrases, %d words each, %.1f bits of entropy", count, opts.Words, passphraseEntropy(opts)), nil
+	}
+
+	return panel, generate
+}
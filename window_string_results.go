@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// resultList shows generated values one per row, each with its own copy
+// button. Copies go through copySecret so they don't outlive the timeout.
+type resultList struct {
+	values   []string
+	selected int
+	masked   bool
+
+	list    *widget.List
+	maskBtn *widget.Button
+
+	// Actions goes next to the "Result" title, Content fills the result pane
+	Actions fyne.CanvasObject
+	Content fyne.CanvasObject
+}
+
+func newResultList(w fyne.Window) *resultList {
+	r := &resultList{selected: -1}
+
+	r.list = widget.NewList(
+		func() int {
+			return len(r.values)
+		},
+		func() fyne.CanvasObject {
+			lbl := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+			lbl.Truncation = fyne.TextTruncateEllipsis
+
+			return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil), lbl)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			lbl := row.Objects[0].(*widget.Label)
+			copyBtn := row.Objects[1].(*widget.Button)
+
+			value := r.values[i]
+			if r.masked {
+				lbl.SetText(maskSecret(value))
+			} else {
+				lbl.SetText(value)
+			}
+
+			copyBtn.OnTapped = func() {
+				copySecret(w, value)
+			}
+		},
+	)
+	r.list.OnSelected = func(id widget.ListItemID) {
+		r.selected = id
+	}
+
+	r.maskBtn = widget.NewButtonWithIcon("Mask", theme.VisibilityOffIcon(), func() {
+		r.SetMasked(!r.masked)
+	})
+
+	copyAllBtn := widget.NewButtonWithIcon("Copy All", theme.ContentCopyIcon(), func() {
+		copySecret(w, strings.Join(r.values, "\n"))
+	})
+
+	clearAfterEntry := widget.NewEntry()
+	clearAfterEntry.SetText(fmt.Sprint(int(clipboardClearAfter.Seconds())))
+	clearAfterEntry.OnChanged = func(text string) {
+		var seconds int
+		if _, err := fmt.Sscan(text, &seconds); err == nil && seconds >= 0 {
+			clipboardClearAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	r.Actions = container.NewHBox(r.maskBtn, copyAllBtn)
+	r.Content = container.NewBorder(
+		nil,
+		container.NewHBox(
+			widget.NewIcon(theme.InfoIcon()),
+			widget.NewLabel("Clear copied values after (seconds, 0 keeps them):"),
+			container.NewGridWrap(fyne.NewSize(60, clearAfterEntry.MinSize().Height), clearAfterEntry),
+		),
+		nil, nil,
+		r.list,
+	)
+
+	return r
+}
+
+func (r *resultList) Set(values []string) {
+	r.values = values
+	r.selected = -1
+	r.list.UnselectAll()
+	r.list.ScrollToTop()
+	r.list.Refresh()
+}
+
+func (r *resultList) Values() []string {
+	return r.values
+}
+
+// Selected returns the selected row, or the first one when none is selected.
+func (r *resultList) Selected() string {
+	if r.selected >= 0 && r.selected < len(r.values) {
+		return r.values[r.selected]
+	}
+	if len(r.values) > 0 {
+		return r.values[0]
+	}
+
+	return ""
+}
+
+func (r *resultList) SetMasked(masked bool) {
+	r.masked = masked
+	if masked {
+		r.maskBtn.SetText("Reveal")
+		r.maskBtn.SetIcon(theme.VisibilityIcon())
+	} else {
+		r.maskBtn.SetText("Mask")
+		r.maskBtn.SetIcon(theme.VisibilityOffIcon())
+	}
+
+	r.list.Refresh()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func strengthCheckerPanel() (fyne.CanvasObject, func(password string)) {
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Paste a password, or select a generated line and press Check")
+
+	meter := widget.NewProgressBar()
+	meter.Max = 4
+	meter.TextFormatter = func() string {
+		return ""
+	}
+
+	scoreLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	details := widget.NewLabel("")
+	details.Wrapping = fyne.TextWrapWord
+
+	analyze := func(password string) {
+		if password == "" {
+			meter.SetValue(0)
+			scoreLabel.SetText("")
+			details.SetText("")
+			return
+		}
+
+		result := AnalyzePassword(password)
+
+		meter.SetValue(float64(result.Score))
+		scoreLabel.SetText(fmt.Sprintf("%s • %.1f bits • %.2g guesses", listStrengthScore[result.Score], result.Bits, result.Guesses))
+
+		var lines []string
+		lines = append(lines, "Patterns:")
+		for _, m := range result.Sequence {
+			lines = append(lines, fmt.Sprintf("  %s %q: %s", m.Pattern, m.Token, m.Detail))
+		}
+
+		lines = append(lines, "", "Estimated crack time:")
+		for _, crack := range result.CrackTimes {
+			lines = append(lines, fmt.Sprintf("  %s: %s", crack.Scenario, formatCrackTime(crack.Seconds)))
+		}
+
+		if len(result.Feedback) > 0 {
+			lines = append(lines, "", "Suggestions:")
+			for _, feedback := range result.Feedback {
+				lines = append(lines, "  • "+feedback)
+			}
+		}
+
+		details.SetText(strings.Join(lines, "\n"))
+	}
+
+	passwordEntry.OnChanged = analyze
+
+	check := func(password string) {
+		passwordEntry.SetText(password)
+	}
+
+	panel := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Strength Checker", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			passwordEntry,
+			container.NewBorder(nil, nil, widget.NewIcon(theme.InfoIcon()), nil, scoreLabel),
+			meter,
+		),
+		nil, nil, nil,
+		container.NewVScroll(details),
+	)
+
+	return panel, check
+}
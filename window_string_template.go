@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const templateRegexHelp = "Literals, [A-Z0-9] classes, \\d \\w, ., (a|b) groups and ? * + {n} {n,m} quantifiers"
+
+func templateModePanel(w fyne.Window) (fyne.CanvasObject, func(count int) ([]string, string, error)) {
+	patternEntry := widget.NewEntry()
+	patternEntry.SetPlaceHolder("INV-####-??")
+
+	helpLabel := widget.NewLabel(templateMaskHelp)
+	helpLabel.Wrapping = fyne.TextWrapWord
+
+	// monospace so the caret lines up under the bad part of the pattern
+	validation := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+
+	kindOptions := widget.NewRadioGroup(listTemplateKind, nil)
+	kindOptions.Horizontal = true
+
+	validate := func() {
+		if patternEntry.Text == "" {
+			validation.SetText("")
+			preview.SetText("")
+			return
+		}
+
+		next, err := compileTemplate(kindOptions.Selected, patternEntry.Text)
+		if err != nil {
+			validation.SetText(err.Error())
+			preview.SetText("")
+			return
+		}
+
+		sample, err := next()
+		if err != nil {
+			validation.SetText(err.Error())
+			preview.SetText("")
+			return
+		}
+
+		validation.SetText("")
+		preview.SetText("Example: " + sample)
+	}
+
+	kindOptions.OnChanged = func(kind string) {
+		if kind == "Regex" {
+			patternEntry.SetPlaceHolder("AKIA[A-Z0-9]{16}")
+			helpLabel.SetText(templateRegexHelp)
+		} else {
+			patternEntry.SetPlaceHolder("INV-####-??")
+			helpLabel.SetText(templateMaskHelp)
+		}
+		validate()
+	}
+	kindOptions.SetSelected("Mask")
+
+	patternEntry.OnChanged = func(string) {
+		validate()
+	}
+
+	panel := container.NewVBox(
+		widget.NewLabelWithStyle("Template", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		kindOptions,
+		widget.NewForm(widget.NewFormItem("Pattern:", patternEntry)),
+		helpLabel,
+		validation,
+		preview,
+	)
+
+	generate := func(count int) ([]string, string, error) {
+		next, err := compileTemplate(kindOptions.Selected, patternEntry.Text)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var out []string
+		for i := 0; i < count; i++ {
+			s, err := next()
+			if err != nil {
+				return nil, "", err
+			}
+			out = append(out, s)
+		}
+
+		return out, fmt.Sprintf("Generated %d strings from %s %q", count, kindOptions.Selected, patternEntry.Text), nil
+	}
+
+	return panel, generate
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type BreakTimer struct {
+	mu sync.Mutex
+
+	WorkDuration  time.Duration
+	BreakDuration time.Duration
+
+	remaining time.Duration
+	running   bool
+	onBreak   bool
+
+	// listeners are keyed by owner so re-opening a window replaces its old listener
+	listeners map[string]func()
+}
+
+var timeBreak = &BreakTimer{
+	WorkDuration:  50 * time.Minute,
+	BreakDuration: 10 * time.Minute,
+	remaining:     50 * time.Minute,
+	listeners:     map[string]func(){},
+}
+
+// onBreakStart and onBreakEnd are called on the fyne thread when the cycle switches phase.
+var onBreakStart = func() {}
+var onBreakEnd = func() {}
+
+func (t *BreakTimer) OnChange(owner string, fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.listeners[owner] = fn
+}
+
+// RemoveListener drops the listener of owner, once its widgets are gone.
+func (t *BreakTimer) RemoveListener(owner string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.listeners, owner)
+}
+
+func (t *BreakTimer) notify() {
+	t.mu.Lock()
+	listeners := make([]func(), 0, len(t.listeners))
+	for _, fn := range t.listeners {
+		listeners = append(listeners, fn)
+	}
+	t.mu.Unlock()
+
+	fyne.Do(func() {
+		for _, fn := range listeners {
+			fn()
+		}
+	})
+}
+
+func (t *BreakTimer) Status() (remaining time.Duration, running bool, onBreak bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.remaining, t.running, t.onBreak
+}
+
+func (t *BreakTimer) Durations() (work, rest time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.WorkDuration, t.BreakDuration
+}
+
+func (t *BreakTimer) Start() {
+	t.mu.Lock()
+	t.running = true
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+func (t *BreakTimer) Pause() {
+	t.mu.Lock()
+	t.running = false
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+func (t *BreakTimer) Toggle() {
+	_, running, _ := t.Status()
+	if running {
+		t.Pause()
+	} else {
+		t.Start()
+	}
+}
+
+// Reset stops the timer and starts over at the beginning of a work phase.
+func (t *BreakTimer) Reset(work, rest time.Duration) {
+	t.mu.Lock()
+	t.WorkDuration = work
+	t.BreakDuration = rest
+	t.remaining = work
+	t.running = false
+	t.onBreak = false
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+func (t *BreakTimer) tick(elapsed time.Duration) {
+	t.mu.Lock()
+	if !t.running {
+		t.mu.Unlock()
+		return
+	}
+
+	t.remaining -= elapsed
+
+	var phaseChanged bool
+	if t.remaining <= 0 {
+		t.onBreak = !t.onBreak
+		phaseChanged = true
+
+		if t.onBreak {
+			t.remaining = t.BreakDuration
+		} else {
+			t.remaining = t.WorkDuration
+		}
+	}
+	onBreak := t.onBreak
+	t.mu.Unlock()
+
+	if phaseChanged {
+		fyne.Do(func() {
+			if onBreak {
+				onBreakStart()
+			} else {
+				onBreakEnd()
+			}
+		})
+	}
+
+	t.notify()
+}
+
+// Run drives the timer until the app quits, it must be started once from main.
+func (t *BreakTimer) Run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		t.tick(time.Second)
+	}
+}
+
+func formatRemaining(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+func breakStatusText() string {
+	remaining, running, onBreak := timeBreak.Status()
+
+	phase := "Work"
+	if onBreak {
+		phase = "Break"
+	}
+
+	state := ""
+	if !running {
+		state = " (paused)"
+	}
+
+	return fmt.Sprintf("%s %s%s", phase, formatRemaining(remaining), state)
+}
+
+func windowTimeBreak(w fyne.Window) func() {
+	return func() {
+		notePreviewToolbar.ToolbarObject().Hide()
+
+		workDuration, breakDuration := timeBreak.Durations()
+
+		workEntry := widget.NewEntry()
+		workEntry.SetPlaceHolder("Work minutes (e.g. 50)")
+		workEntry.SetText(fmt.Sprint(int(workDuration.Minutes())))
+
+		breakEntry := widget.NewEntry()
+		breakEntry.SetPlaceHolder("Break minutes (e.g. 10)")
+		breakEntry.SetText(fmt.Sprint(int(breakDuration.Minutes())))
+
+		phaseLabel := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+		phaseLabel.SizeName = theme.SizeNameSubHeadingText
+
+		remainingLabel := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Monospace: true})
+		remainingLabel.SizeName = theme.SizeNameHeadingText
+
+		startBtn := widget.NewButtonWithIcon("Start", theme.MediaPlayIcon(), func() {
+			timeBreak.Toggle()
+		})
+
+		refresh := func() {
+			remaining, running, onBreak := timeBreak.Status()
+
+			if onBreak {
+				phaseLabel.SetText("Time for Break")
+			} else {
+				phaseLabel.SetText("Working")
+			}
+			remainingLabel.SetText(formatRemaining(remaining))
+
+			if running {
+				startBtn.SetText("Pause")
+				startBtn.SetIcon(theme.MediaPauseIcon())
+			} else {
+				startBtn.SetText("Start")
+				startBtn.SetIcon(theme.MediaPlayIcon())
+			}
+		}
+
+		resetBtn := widget.NewButtonWithIcon("Apply & Reset", theme.MediaReplayIcon(), func() {
+			var work, rest int
+
+			_, err := fmt.Sscan(workEntry.Text, &work)
+			if err != nil || work <= 0 {
+				dialog.ShowError(fmt.Errorf("invalid work minutes: %v", workEntry.Text), w)
+				return
+			}
+
+			_, err = fmt.Sscan(breakEntry.Text, &rest)
+			if err != nil || rest <= 0 {
+				dialog.ShowError(fmt.Errorf("invalid break minutes: %v", breakEntry.Text), w)
+				return
+			}
+
+			timeBreak.Reset(time.Duration(work)*time.Minute, time.Duration(rest)*time.Minute)
+		})
+
+		exerciseCheck := widget.NewCheck("Guide exercises during break", func(checked bool) {
+			exerciseDuringBreak = checked
+		})
+		exerciseCheck.SetChecked(exerciseDuringBreak)
+
+		tryExerciseBtn := widget.NewButtonWithIcon("Try Routine", theme.MediaPlayIcon(), func() {
+			steps, err := loadExercises()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+
+			showExerciseRoutine(w, steps)
+		})
+
+		editExerciseBtn := widget.NewButtonWithIcon("Edit Exercises", theme.DocumentCreateIcon(), func() {
+			showExerciseEditor(w)
+		})
+
+		refresh()
+
+		configs := widget.NewForm()
+		configs.Append("Work (minutes):", workEntry)
+		configs.Append("Break (minutes):", breakEntry)
+
+		left := container.NewVBox(
+			widget.NewLabelWithStyle("Configuration", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			vPadding(10),
+			configs,
+			vPadding(10),
+			widget.NewSeparator(),
+			vPadding(10),
+			container.NewHBox(startBtn, resetBtn),
+			vPadding(10),
+			widget.NewSeparator(),
+			widget.NewLabelWithStyle("Exercises", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			exerciseCheck,
+			container.NewHBox(tryExerciseBtn, editExerciseBtn),
+		)
+
+		right := container.NewCenter(container.NewVBox(phaseLabel, remainingLabel))
+
+		rightPanel := padding(10, right)
+		leftPanel := padding(15, left)
+
+		split := container.NewHSplit(leftPanel, rightPanel)
+		split.SetOffset(0.3)
+
+		setToolContent(w,
+			container.NewBorder(toolbars(w), nil, nil, nil, split),
+		)
+
+		// registered after the previous tool left, its leave hook may be this one's
+		timeBreak.OnChange("window", refresh)
+		onToolLeave = func() {
+			timeBreak.RemoveListener("window")
+		}
+	}
+}